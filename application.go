@@ -1,23 +1,27 @@
 package main
 
 import (
-	"crypto/tls"
-	"crypto/x509"
-	"errors"
-	"fmt"
 	"os"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/commands/alter"
+	"github.com/xitonix/trubka/commands/archive"
+	"github.com/xitonix/trubka/commands/check"
+	"github.com/xitonix/trubka/commands/configuration"
 	"github.com/xitonix/trubka/commands/consume"
+	"github.com/xitonix/trubka/commands/copying"
 	"github.com/xitonix/trubka/commands/create"
 	"github.com/xitonix/trubka/commands/deletion"
 	"github.com/xitonix/trubka/commands/describe"
+	"github.com/xitonix/trubka/commands/elect"
 	"github.com/xitonix/trubka/commands/list"
 	"github.com/xitonix/trubka/commands/produce"
+	"github.com/xitonix/trubka/commands/reassign"
+	"github.com/xitonix/trubka/commands/reset"
 	"github.com/xitonix/trubka/internal"
-	"github.com/xitonix/trubka/kafka"
+	"github.com/xitonix/trubka/internal/config"
 )
 
 func newApplication() error {
@@ -25,15 +29,23 @@ func newApplication() error {
 	global := &commands.GlobalParameters{}
 	bindAppFlags(app, global)
 	commands.AddVersionCommand(app, version, commit, built, runtimeVer)
-	kafkaParams := bindKafkaFlags(app)
+	kafkaParams := bindKafkaFlags(app, global)
 	list.AddCommands(app, global, kafkaParams)
 	describe.AddCommands(app, global, kafkaParams)
 	deletion.AddCommands(app, global, kafkaParams)
 	consume.AddCommands(app, global, kafkaParams)
 	create.AddCommands(app, global, kafkaParams)
 	produce.AddCommands(app, global, kafkaParams)
+	reset.AddCommands(app, global, kafkaParams)
+	alter.AddCommands(app, global, kafkaParams)
+	reassign.AddCommands(app, global, kafkaParams)
+	elect.AddCommands(app, global, kafkaParams)
+	copying.AddCommands(app, global, kafkaParams)
+	archive.AddCommands(app, global, kafkaParams)
+	check.AddCommands(app, global, kafkaParams)
+	configuration.AddCommands(app, global)
 	_, err := app.Parse(os.Args[1:])
-	return err
+	return commands.WithExitCode(err, global.ErrorExitCode)
 }
 
 func bindAppFlags(app *kingpin.Application, global *commands.GlobalParameters) {
@@ -60,96 +72,28 @@ func bindAppFlags(app *kingpin.Application, global *commands.GlobalParameters) {
 			return nil
 		}).
 		CounterVar(&verbosity)
+
+	app.Flag("config", "The path to the configuration file of the cluster profiles.").
+		Default(config.DefaultPath()).
+		PlaceHolder("FILE").
+		StringVar(&global.ConfigFile)
+
+	app.Flag("profile", "The cluster profile to load the connection settings and the default flag values from. The flags which are explicitly set take precedence over the profile (Default: the current profile, unless the brokers are explicitly set).").
+		StringVar(&global.ProfileName)
 }
 
-func bindKafkaFlags(app *kingpin.Application) *commands.KafkaParameters {
+func bindKafkaFlags(app *kingpin.Application, global *commands.GlobalParameters) *commands.KafkaParameters {
 	params := &commands.KafkaParameters{}
 	app.Flag("brokers", "The comma separated list of Kafka brokers in server:port format.").
 		Short('b').
 		StringVar(&params.Brokers)
-	app.Flag("kafka-version", "Kafka cluster version.").
-		Default(kafka.DefaultClusterVersion).
-		StringVar(&params.Version)
-
-	bindSASLFlags(app, params)
 
-	tlsParams := bindTLSFlags(app)
+	tlsParams, _ := commands.BindKafkaFlags(app, params, "", "")
 	app.PreAction(func(ctx *kingpin.ParseContext) error {
-		if !tlsParams.Enabled {
-			return nil
-		}
-		tlsConfig, err := configureTLS(tlsParams)
-		if err != nil {
+		if err := applyProfile(ctx, app, global); err != nil {
 			return err
 		}
-		params.TLS = tlsConfig
-		return nil
+		return params.Configure(tlsParams, "")
 	})
 	return params
 }
-
-func bindTLSFlags(app *kingpin.Application) *commands.TLSParameters {
-	t := &commands.TLSParameters{}
-	app.Flag("tls", "Enables TLS (Unverified by default). Mutual authentication can also be enabled by providing client key and certificate.").
-		BoolVar(&t.Enabled)
-	app.Flag("ca-cert", `Trusted root certificates for verifying the server. If not set, Trubka will skip server certificate and domain verification.`).
-		ExistingFileVar(&t.CACert)
-	app.Flag("client-cert", `Client certification file to enable mutual TLS authentication. Client key must also be provided.`).
-		ExistingFileVar(&t.ClientCert)
-	app.Flag("client-key", `Client private key file to enable mutual TLS authentication. Client certificate must also be provided.`).
-		ExistingFileVar(&t.ClientKey)
-	return t
-}
-
-func bindSASLFlags(app *kingpin.Application, params *commands.KafkaParameters) {
-	app.Flag("sasl-mechanism", "SASL authentication mechanism.").
-		Default(kafka.SASLMechanismNone).
-		EnumVar(&params.SASLMechanism,
-			kafka.SASLMechanismNone,
-			kafka.SASLMechanismPlain,
-			kafka.SASLMechanismSCRAM256,
-			kafka.SASLMechanismSCRAM512)
-	app.Flag("sasl-username", "SASL authentication username. Will be ignored if --sasl-mechanism is set to none.").
-		StringVar(&params.SASLUsername)
-	app.Flag("sasl-password", "SASL authentication password. Will be ignored if --sasl-mechanism is set to none.").
-		StringVar(&params.SASLPassword)
-	app.Flag("sasl-version", "SASL handshake version. Will be ignored if --sasl-mechanism is set to none.").
-		Default(string(kafka.SASLHandshakeV1)).
-		EnumVar(&params.SASLHandshakeVersion, string(kafka.SASLHandshakeV0), string(kafka.SASLHandshakeV1))
-}
-
-func configureTLS(params *commands.TLSParameters) (*tls.Config, error) {
-	tlsConf := tls.Config{}
-
-	// Mutual authentication is enabled. Both client key and certificate are needed.
-	if !internal.IsEmpty(params.ClientCert) {
-		if internal.IsEmpty(params.ClientKey) {
-			return nil, errors.New("TLS client key is missing. Mutual authentication cannot be used")
-		}
-		certificate, err := tls.LoadX509KeyPair(params.ClientCert, params.ClientKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load the client TLS key pair: %w", err)
-		}
-		tlsConf.Certificates = []tls.Certificate{certificate}
-	}
-
-	if internal.IsEmpty(params.CACert) {
-		// Server cert verification will be disabled.
-		// Only standard trusted certificates are used to verify the server certs.
-		tlsConf.InsecureSkipVerify = true
-		return &tlsConf, nil
-	}
-	certPool := x509.NewCertPool()
-	ca, err := os.ReadFile(params.CACert)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read the CA certificate: %w", err)
-	}
-
-	if ok := certPool.AppendCertsFromPEM(ca); !ok {
-		return nil, errors.New("failed to append the CA certificate to the pool")
-	}
-
-	tlsConf.RootCAs = certPool
-
-	return &tlsConf, nil
-}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+var (
+	aclResourceTypes = []string{"any", "topic", "group", "cluster", "transactional-id", "delegation-token"}
+	aclPatternTypes  = []string{"any", "match", "literal", "prefixed"}
+	aclOperations    = []string{"any", "all", "read", "write", "create", "delete", "alter", "describe", "cluster-action", "describe-configs", "alter-configs", "idempotent-write"}
+	aclPermissions   = []string{"any", "allow", "deny"}
+)
+
+// BindACLFlags binds the ACL flags to the specified command.
+//
+// The flags will be used to filter the ACL entries if filter is true, in which case the unset flags match any value.
+func BindACLFlags(c *kingpin.CmdClause, acl *kafka.ACL, filter bool) {
+	resourceType := c.Flag("resource-type", fmt.Sprintf("The resource type (%s).", strings.Join(aclResourceTypes, ", "))).NoEnvar()
+	resourceName := c.Flag("resource-name", "The resource name. Not required for cluster resources.").NoEnvar()
+	principal := c.Flag("principal", "The principal in Type:Name format (eg. User:alice).").NoEnvar()
+	operation := c.Flag("operation", fmt.Sprintf("The operation (%s).", strings.Join(aclOperations, ", "))).NoEnvar()
+	patternType := c.Flag("pattern-type", fmt.Sprintf("The resource pattern type (%s).", strings.Join(aclPatternTypes, ", "))).NoEnvar()
+	permission := c.Flag("permission", fmt.Sprintf("The permission type (%s).", strings.Join(aclPermissions, ", "))).NoEnvar()
+	host := c.Flag("host", "The host from which the principal connects.").NoEnvar()
+	if filter {
+		resourceType.Short('r')
+		resourceName.Short('n')
+		principal.Short('p')
+	} else {
+		resourceType.Required()
+		principal.Required()
+		operation.Required()
+		patternType.Default("literal")
+		permission.Default("allow")
+		host.Default("*")
+	}
+	resourceType.EnumVar(&acl.ResourceType, aclResourceTypes...)
+	resourceName.StringVar(&acl.ResourceName)
+	principal.StringVar(&acl.Principal)
+	operation.EnumVar(&acl.Operation, aclOperations...)
+	patternType.EnumVar(&acl.PatternType, aclPatternTypes...)
+	permission.EnumVar(&acl.Permission, aclPermissions...)
+	host.StringVar(&acl.Host)
+}
+
+// PrintACLs prints the ACL entries in the requested format.
+func PrintACLs(acls []*kafka.ACL, outputFormat, style string, enableColor bool) error {
+	switch outputFormat {
+	case JSONFormat:
+		return output.PrintAsJSON(acls, style, enableColor)
+	case TableFormat:
+		printACLTable(acls, enableColor)
+	case TreeFormat:
+		printACLList(acls, false)
+	case PlainTextFormat:
+		printACLList(acls, true)
+	}
+	return nil
+}
+
+func printACLTable(acls []*kafka.ACL, enableColor bool) {
+	table := tabular.NewTable(enableColor,
+		tabular.C("Resource").Align(tabular.AlignLeft),
+		tabular.C("Name").Align(tabular.AlignLeft).MaxWidth(100),
+		tabular.C("Pattern"),
+		tabular.C("Principal").Align(tabular.AlignLeft),
+		tabular.C("Host"),
+		tabular.C("Operation"),
+		tabular.C("Permission"),
+	)
+	table.SetTitle(format.WithCount("ACLs", len(acls)))
+	for _, acl := range acls {
+		table.AddRow(acl.ResourceType, acl.ResourceName, acl.PatternType, acl.Principal, acl.Host, acl.Operation, acl.Permission)
+	}
+	table.AddFooter(fmt.Sprintf("Total: %d", len(acls)), " ", " ", " ", " ", " ", " ")
+	table.Render()
+}
+
+func printACLList(acls []*kafka.ACL, plain bool) {
+	l := list.New(plain)
+	for _, acl := range acls {
+		l.AddItemF("%s:%s (%s)", acl.ResourceType, acl.ResourceName, acl.PatternType)
+		l.Indent()
+		l.AddItemF("Principal: %s", acl.Principal)
+		l.AddItemF("     Host: %s", acl.Host)
+		l.AddItemF("Operation: %s", acl.Operation)
+		l.AddItemF("   Access: %s", acl.Permission)
+		l.UnIndent()
+	}
+	l.Render()
+}
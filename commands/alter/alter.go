@@ -0,0 +1,86 @@
+package alter
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+const deletedValue = "(default)"
+
+// AddCommands adds the alter command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	parent := app.Command("alter", "A command to alter Kafka entities.")
+	addAlterTopicConfigSubCommand(parent, global, kafkaParams)
+	addAlterBrokerConfigSubCommand(parent, global, kafkaParams)
+}
+
+func bindConfigFlags(c *kingpin.CmdClause, set *map[string]string, remove *[]string, validateOnly *bool) {
+	c.Flag("set", "The config to set in key=value format. Repeat the flag to set multiple configs (eg. --set retention.ms=1000 --set cleanup.policy=compact).").
+		PlaceHolder("KEY=VALUE").
+		NoEnvar().
+		StringMapVar(set)
+	c.Flag("delete", "The config to revert to its default value. Repeat the flag to delete multiple configs.").
+		PlaceHolder("KEY").
+		NoEnvar().
+		StringsVar(remove)
+	c.Flag("validate-only", "Validates the request instead of applying the changes.").
+		Short('A').
+		NoEnvar().
+		BoolVar(validateOnly)
+}
+
+func printChanges(title string, changes []*kafka.ConfigChange, validateOnly bool, globalParams *commands.GlobalParameters, outputFormat, style string) error {
+	switch outputFormat {
+	case commands.JSONFormat:
+		return output.PrintAsJSON(changes, style, globalParams.EnableColor)
+	case commands.TableFormat:
+		printAsTable(title, changes, globalParams.EnableColor)
+	case commands.TreeFormat:
+		printAsList(title, changes, false)
+	case commands.PlainTextFormat:
+		printAsList(title, changes, true)
+	}
+	if validateOnly {
+		fmt.Println("The server WILL ACCEPT the request.")
+	}
+	return nil
+}
+
+func printAsTable(title string, changes []*kafka.ConfigChange, enableColor bool) {
+	table := tabular.NewTable(enableColor,
+		tabular.C("Name").Align(tabular.AlignLeft),
+		tabular.C("Old Value").Align(tabular.AlignLeft).MaxWidth(100),
+		tabular.C("New Value").Align(tabular.AlignLeft).MaxWidth(100),
+	)
+	table.SetTitle(title)
+	for _, change := range changes {
+		table.AddRow(change.Name, change.OldValue, newValue(change))
+	}
+	table.AddFooter(fmt.Sprintf("Total: %d", len(changes)), " ", " ")
+	table.Render()
+}
+
+func printAsList(title string, changes []*kafka.ConfigChange, plain bool) {
+	l := list.New(plain)
+	l.AddItem(title)
+	l.Indent()
+	for _, change := range changes {
+		l.AddItemF("%s: %s -> %s", change.Name, change.OldValue, newValue(change))
+	}
+	l.UnIndent()
+	l.Render()
+}
+
+func newValue(change *kafka.ConfigChange) string {
+	if change.Deleted {
+		return deletedValue
+	}
+	return change.NewValue
+}
@@ -0,0 +1,58 @@
+package alter
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+)
+
+type brokerConfig struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	identifier   string
+	set          map[string]string
+	remove       []string
+	validateOnly bool
+	format       string
+	style        string
+}
+
+func addAlterBrokerConfigSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &brokerConfig{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("broker-config", "Sets or deletes the dynamic configurations of a broker.").Action(cmd.run)
+	c.Arg("broker", "The broker address or ID.").
+		Required().
+		StringVar(&cmd.identifier)
+	bindConfigFlags(c, &cmd.set, &cmd.remove, &cmd.validateOnly)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (c *brokerConfig) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(c.identifier) {
+		return errors.New("broker address or ID cannot be empty")
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(c.globalParams, c.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	changes, err := manager.AlterBrokerConfig(ctx, c.identifier, c.set, c.remove, c.validateOnly)
+	if err != nil {
+		return err
+	}
+
+	return printChanges(fmt.Sprintf("Broker: %s", c.identifier), changes, c.validateOnly, c.globalParams, c.format, c.style)
+}
@@ -0,0 +1,58 @@
+package alter
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+)
+
+type topicConfig struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topic        string
+	set          map[string]string
+	remove       []string
+	validateOnly bool
+	format       string
+	style        string
+}
+
+func addAlterTopicConfigSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &topicConfig{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("topic-config", "Sets or deletes the configurations of a topic.").Action(cmd.run)
+	c.Arg("topic", "The topic name.").
+		Required().
+		StringVar(&cmd.topic)
+	bindConfigFlags(c, &cmd.set, &cmd.remove, &cmd.validateOnly)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (c *topicConfig) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(c.topic) {
+		return errors.New("topic cannot be empty")
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(c.globalParams, c.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	changes, err := manager.AlterTopicConfig(ctx, c.topic, c.set, c.remove, c.validateOnly)
+	if err != nil {
+		return err
+	}
+
+	return printChanges(fmt.Sprintf("Topic: %s", c.topic), changes, c.validateOnly, c.globalParams, c.format, c.style)
+}
@@ -0,0 +1,13 @@
+package archive
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+)
+
+// AddCommands adds the export and import commands to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	addExportCommand(app, global, kafkaParams)
+	addImportCommand(app, global, kafkaParams)
+}
@@ -0,0 +1,121 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type export struct {
+	globalParams   *commands.GlobalParameters
+	kafkaParams    *commands.KafkaParameters
+	consumerParams *commands.ConsumerParameters
+	topic          string
+	outputFile     string
+	format         string
+}
+
+func addExportCommand(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &export{
+		globalParams:   global,
+		kafkaParams:    kafkaParams,
+		consumerParams: &commands.ConsumerParameters{},
+	}
+	c := app.Command("export", "Exports the messages of a topic, including the keys, headers, timestamps, partitions and offsets, to a file which can be imported back using the import command.").Action(cmd.run)
+	c.Arg("topic", "The topic to export the messages of.").
+		Required().
+		StringVar(&cmd.topic)
+	c.Flag("output-file", "The file to export the messages to (Default: Stdout).").
+		Short('o').
+		NoEnvar().
+		StringVar(&cmd.outputFile)
+	c.Flag("format", fmt.Sprintf("The archive format. %s writes newline delimited Json objects with base64 encoded keys, values and header values. %s writes length-prefixed binary records.", kafka.JSONArchive, kafka.BinaryArchive)).
+		Short('f').
+		Default(kafka.JSONArchive).
+		NoEnvar().
+		EnumVar(&cmd.format, kafka.JSONArchive, kafka.BinaryArchive)
+	commands.BindConsumerFlags(c, cmd.consumerParams, "oldest", "10s")
+}
+
+func (e *export) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(e.topic) {
+		return errors.New("the topic name cannot be empty")
+	}
+
+	checkpoints, err := e.consumerParams.NewCheckpoints()
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if !internal.IsEmpty(e.outputFile) {
+		file, err := commands.CreateFile(e.outputFile)
+		if err != nil {
+			return err
+		}
+		defer commands.CloseFile(file, e.globalParams.EnableColor)
+		output = file
+	}
+
+	writer, err := kafka.NewArchiveWriter(output, e.format)
+	if err != nil {
+		return err
+	}
+
+	saramaLogWriter := io.Discard
+	if e.globalParams.Verbosity >= internal.Chatty {
+		saramaLogWriter = os.Stderr
+	}
+
+	prn := internal.NewPrinter(e.globalParams.Verbosity, os.Stderr)
+	consumer, err := commands.NewConsumer(e.kafkaParams, e.consumerParams, saramaLogWriter, prn)
+	if err != nil {
+		return err
+	}
+
+	// It is safe to close the consumer more than once.
+	defer consumer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		internal.WaitForCancellationSignal()
+		prn.Info(internal.Verbose, "Stopping Trubka.")
+		cancel()
+	}()
+
+	prn.Start(map[string]io.Writer{})
+
+	var exported int64
+	err = commands.ConsumeEvents(ctx, consumer, prn, map[string]*kafka.PartitionCheckpoints{e.topic: checkpoints}, func(event *kafka.Event) (bool, error) {
+		if err := writer.Write(event); err != nil {
+			return false, fmt.Errorf("failed to write the message at offset %d of partition %d: %w", event.Offset, event.Partition, err)
+		}
+		exported++
+		return true, nil
+	})
+	prn.Close()
+
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	if e.globalParams.Verbosity >= internal.Verbose || !internal.IsEmpty(e.outputFile) {
+		fmt.Fprintf(os.Stderr, "%d messages have been exported.\n", exported)
+	}
+	return nil
+}
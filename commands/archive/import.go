@@ -0,0 +1,121 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type importArchive struct {
+	globalParams       *commands.GlobalParameters
+	kafkaParams        *commands.KafkaParameters
+	file               string
+	topic              string
+	preservePartitions bool
+}
+
+func addImportCommand(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &importArchive{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := app.Command("import", "Publishes the messages of an archive, created by the export command, to a topic. The archive format is detected automatically.").Action(cmd.run)
+	c.Arg("file", "The archive file to import. Set to '-' to read from Stdin.").
+		Required().
+		StringVar(&cmd.file)
+	c.Arg("topic", "The topic to publish the messages to.").
+		Required().
+		StringVar(&cmd.topic)
+	c.Flag("preserve-partitions", "Publishes each message to the same partition number it was exported from, if the partition exists. Otherwise, the partition will be selected based on the message key. Use --no-preserve-partitions to disable.").
+		Default("true").
+		NoEnvar().
+		BoolVar(&cmd.preservePartitions)
+}
+
+func (i *importArchive) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(i.topic) {
+		return errors.New("the topic name cannot be empty")
+	}
+
+	var input io.Reader = os.Stdin
+	if i.file != "-" {
+		file, err := os.Open(i.file)
+		if err != nil {
+			return fmt.Errorf("failed to open the archive: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	reader, err := kafka.NewArchiveReader(input)
+	if err != nil {
+		return err
+	}
+
+	if i.globalParams.Verbosity >= internal.Verbose {
+		fmt.Printf("Importing the messages from the %s archive.\n", reader.Format())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		internal.WaitForCancellationSignal()
+		cancel()
+	}()
+
+	producer, err := kafka.NewProducer(commands.GetBrokers(i.kafkaParams.Brokers),
+		kafka.WithClusterVersion(i.kafkaParams.Version),
+		kafka.WithTLS(i.kafkaParams.TLS),
+		kafka.WithSASL(i.kafkaParams.SASLMechanism,
+			i.kafkaParams.SASLUsername,
+			i.kafkaParams.Password(),
+			i.kafkaParams.SASLHandshakeVersion,
+			i.kafkaParams.SASLOptions()...))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err := producer.Close()
+		if err != nil {
+			fmt.Println(format.Red("Failed to close the publisher", i.globalParams.EnableColor))
+		}
+	}()
+
+	var imported int64
+	defer func() {
+		fmt.Printf("%d messages have been imported.\n", imported)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			event, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			partition, offset, err := producer.ProduceEvent(i.topic, event, i.preservePartitions)
+			if err != nil {
+				return fmt.Errorf("failed to publish the message exported from offset %d of partition %d: %w", event.Offset, event.Partition, err)
+			}
+			imported++
+			if i.globalParams.Verbosity >= internal.VeryVerbose {
+				fmt.Printf("Message has been published to the offset %d of partition %d.\n", offset, partition)
+			}
+		}
+	}
+}
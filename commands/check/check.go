@@ -0,0 +1,20 @@
+package check
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+)
+
+// AddCommands adds the check command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	parent := app.Command("check", "A command to check the health of Kafka entities.").
+		Validate(func(_ *kingpin.CmdClause) error {
+			// The command validators run before the pre-actions of the app, so that the failures of
+			// the flag parsing, profile loading and connection settings validation are reported as UNKNOWN (3),
+			// instead of being mistaken for a WARNING (1) by the monitoring systems.
+			global.ErrorExitCode = exitUnknown
+			return nil
+		})
+	addClusterSubCommand(parent, global, kafkaParams)
+}
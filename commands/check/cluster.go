@@ -0,0 +1,246 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+// The Nagios plugin exit codes.
+const (
+	exitWarning  = 1
+	exitCritical = 2
+	exitUnknown  = 3
+)
+
+type cluster struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topicFilter  *regexp.Regexp
+	thresholds   kafka.HealthThresholds
+	format       string
+	style        string
+}
+
+func addClusterSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &cluster{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("cluster", "Checks the replication status of the partitions and the availability of the brokers and the controller. Exits with 0 (OK), 1 (WARNING: issues within the thresholds), 2 (CRITICAL: thresholds exceeded or the cluster is unreachable) or 3 (UNKNOWN: any other failure, including invalid flags or settings and interruptions).").Action(cmd.run)
+	c.Flag("topic-filter", "An optional regular expression to filter the checked topics by.").
+		Short('t').
+		NoEnvar().
+		RegexpVar(&cmd.topicFilter)
+	c.Flag("max-under-replicated", "The maximum number of the under replicated partitions before the check becomes critical.").
+		Default("0").
+		NoEnvar().
+		IntVar(&cmd.thresholds.MaxUnderReplicated)
+	c.Flag("max-offline-replicas", "The maximum number of the partitions with offline replicas before the check becomes critical.").
+		Default("0").
+		NoEnvar().
+		IntVar(&cmd.thresholds.MaxOfflineReplicas)
+	c.Flag("max-leaderless", "The maximum number of the partitions without a leader before the check becomes critical.").
+		Default("0").
+		NoEnvar().
+		IntVar(&cmd.thresholds.MaxLeaderless)
+	c.Flag("max-missing-brokers", "The maximum number of the brokers which host replicas, but are missing from the cluster metadata, before the check becomes critical.").
+		Default("0").
+		NoEnvar().
+		IntVar(&cmd.thresholds.MaxMissingBrokers)
+	c.Flag("min-brokers", "The minimum number of the brokers in the cluster metadata. Disabled (0) by default.").
+		Default("0").
+		NoEnvar().
+		IntVar(&cmd.thresholds.MinBrokers)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (c *cluster) run(_ *kingpin.ParseContext) error {
+	manager, ctx, cancel, err := commands.InitKafkaManager(c.globalParams, c.kafkaParams)
+	if err != nil {
+		return &commands.ExitError{Code: exitCritical, Err: err}
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	health, err := manager.CheckClusterHealth(ctx, c.topicFilter, c.thresholds)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return &commands.ExitError{Code: exitUnknown, Err: errors.New("the check has been interrupted")}
+		}
+		return &commands.ExitError{Code: exitUnknown, Err: err}
+	}
+
+	switch c.format {
+	case commands.JSONFormat:
+		if err := output.PrintAsJSON(health, c.style, c.globalParams.EnableColor); err != nil {
+			return &commands.ExitError{Code: exitUnknown, Err: err}
+		}
+	case commands.TableFormat:
+		c.printAsTable(health)
+	case commands.TreeFormat:
+		c.printAsList(health, false)
+	case commands.PlainTextFormat:
+		c.printAsList(health, true)
+	}
+
+	switch health.Status {
+	case kafka.HealthWarning:
+		return &commands.ExitError{Code: exitWarning}
+	case kafka.HealthCritical:
+		return &commands.ExitError{Code: exitCritical}
+	default:
+		return nil
+	}
+}
+
+func (c *cluster) printAsTable(health *kafka.ClusterHealth) {
+	table := tabular.NewTable(c.globalParams.EnableColor,
+		tabular.C("Check").Align(tabular.AlignLeft),
+		tabular.C("Value").MinWidth(10),
+		tabular.C("Threshold").MinWidth(10),
+		tabular.C("Status").MinWidth(10),
+	)
+	table.SetTitle("Cluster Health")
+	for _, check := range health.Checks {
+		table.AddRow(check.Name, check.Value, check.Threshold, c.status(check.Status, c.globalParams.EnableColor))
+	}
+	table.AddFooter(
+		fmt.Sprintf("Brokers: %d, Topics: %d, Partitions: %d", len(health.Brokers), health.Topics, health.Partitions),
+		" ",
+		" ",
+		c.status(health.Status, false))
+	output.NewLines(1)
+	table.Render()
+
+	unhealthy := health.Unhealthy()
+	if len(unhealthy) == 0 {
+		return
+	}
+	table = tabular.NewTable(c.globalParams.EnableColor,
+		tabular.C("Topic").Align(tabular.AlignLeft),
+		tabular.C("Partition"),
+		tabular.C("Leader"),
+		tabular.C("Replicas").Align(tabular.AlignLeft),
+		tabular.C("ISRs").Align(tabular.AlignLeft),
+		tabular.C("Offline Replicas").Align(tabular.AlignLeft),
+		tabular.C("Issues").Align(tabular.AlignLeft),
+	)
+	table.SetTitle(format.WithCount("Unhealthy Partitions", len(unhealthy)))
+	for _, p := range unhealthy {
+		table.AddRow(
+			p.Topic,
+			p.Partition,
+			format.RedIfTrue(leader(p), func() bool { return p.Leader < 0 }, c.globalParams.EnableColor),
+			format.SpaceIfEmpty(ids(p.Replicas)),
+			format.SpaceIfEmpty(ids(p.ISRs)),
+			format.SpaceIfEmpty(ids(p.OfflineReplicas)),
+			strings.Join(c.issues(health, p), "\n"),
+		)
+	}
+	table.AddFooter(" ", " ", " ", " ", " ", " ", fmt.Sprintf("Total: %d", len(unhealthy)))
+	output.NewLines(1)
+	table.Render()
+
+	if len(health.MissingBrokers) > 0 {
+		fmt.Printf("\nMissing Brokers: %s\n", ids(health.MissingBrokers))
+	}
+}
+
+func (c *cluster) printAsList(health *kafka.ClusterHealth, plain bool) {
+	enableColor := c.globalParams.EnableColor && !plain
+	l := list.New(plain)
+	l.AddItemF("Status: %v", c.status(health.Status, enableColor))
+	l.AddItem("Checks")
+	l.Indent()
+	for _, check := range health.Checks {
+		l.AddItemF("%s: %d (%s) %v", check.Name, check.Value, check.Threshold, c.status(check.Status, enableColor))
+	}
+	l.UnIndent()
+	l.AddItemF("Brokers: %d", len(health.Brokers))
+	if len(health.MissingBrokers) > 0 {
+		l.AddItemF("Missing Brokers: %s", ids(health.MissingBrokers))
+	}
+	l.AddItemF("Topics: %d", health.Topics)
+	l.AddItemF("Partitions: %d", health.Partitions)
+
+	unhealthy := health.Unhealthy()
+	if len(unhealthy) > 0 {
+		l.AddItem("Unhealthy Partitions")
+		l.Indent()
+		for _, p := range unhealthy {
+			l.AddItemF("%s/%d: %s", p.Topic, p.Partition, strings.Join(c.issues(health, p), ", "))
+			l.Indent()
+			l.AddItemF("Leader: %s", leader(p))
+			l.AddItemF("Replicas: %s", ids(p.Replicas))
+			l.AddItemF("ISRs: %s", ids(p.ISRs))
+			if len(p.OfflineReplicas) > 0 {
+				l.AddItemF("Offline Replicas: %s", ids(p.OfflineReplicas))
+			}
+			l.UnIndent()
+		}
+		l.UnIndent()
+	}
+	l.Render()
+}
+
+func (c *cluster) issues(health *kafka.ClusterHealth, partition *kafka.PartitionHealth) []string {
+	issues := make([]string, 0)
+	for _, category := range []struct {
+		name       string
+		partitions []*kafka.PartitionHealth
+	}{
+		{name: "No Leader", partitions: health.Leaderless},
+		{name: "Offline Replicas", partitions: health.OfflineReplicas},
+		{name: "Under Replicated", partitions: health.UnderReplicated},
+	} {
+		for _, p := range category.partitions {
+			if p == partition {
+				issues = append(issues, category.name)
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func (c *cluster) status(status kafka.HealthStatus, enableColor bool) interface{} {
+	switch status {
+	case kafka.HealthOK:
+		return format.BoldGreen(status, enableColor)
+	case kafka.HealthWarning:
+		return format.Yellow(status, enableColor)
+	default:
+		return format.Red(status, enableColor)
+	}
+}
+
+func leader(partition *kafka.PartitionHealth) string {
+	if partition.Leader < 0 {
+		return "None"
+	}
+	return strconv.FormatInt(int64(partition.Leader), 10)
+}
+
+func ids(brokers []int32) string {
+	result := make([]string, len(brokers))
+	for i, id := range brokers {
+		result[i] = strconv.FormatInt(int64(id), 10)
+	}
+	return strings.Join(result, ", ")
+}
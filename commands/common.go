@@ -3,6 +3,7 @@ package commands
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -30,6 +31,62 @@ const (
 	JSONFormat = "json"
 )
 
+// ErrMissingProtoRoot is returned if the proto root has neither been set by the user nor by the active profile.
+var ErrMissingProtoRoot = errors.New("the path to the proto files is required. Use --proto-root or set the proto root of the cluster profile")
+
+// ExitError makes the application exit with a specific exit code.
+type ExitError struct {
+	// Code the exit code.
+	Code int
+	// Err the error to print before exiting. Nothing will be printed if nil.
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// WithExitCode wraps the error into an ExitError with the specified exit code.
+//
+// The error will be returned unchanged if it is nil, the code is zero or the error already has an exit code.
+func WithExitCode(err error, code int) error {
+	var exitErr *ExitError
+	if err == nil || code == 0 || errors.As(err, &exitErr) {
+		return err
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// CreateFile creates the output file, or truncates it if it already exists.
+func CreateFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// CloseFile flushes the content of the file to the disk and closes it.
+func CloseFile(file *os.File, highlight bool) {
+	err := file.Sync()
+	if err != nil {
+		msg := fmt.Sprintf("Failed to sync the file: %s", err)
+		fmt.Println(format.Red(msg, highlight))
+	}
+	if err := file.Close(); err != nil {
+		msg := fmt.Sprintf("Failed to close the file: %s", err)
+		fmt.Println(format.Red(msg, highlight))
+	}
+}
+
 // InitKafkaManager initialises the Kafka manager.
 func InitKafkaManager(globalParams *GlobalParameters, kafkaParams *KafkaParameters) (*kafka.Manager, context.Context, context.CancelFunc, error) {
 	brokers := GetBrokers(kafkaParams.Brokers)
@@ -40,8 +97,9 @@ func InitKafkaManager(globalParams *GlobalParameters, kafkaParams *KafkaParamete
 		kafka.WithClusterVersion(kafkaParams.Version),
 		kafka.WithSASL(kafkaParams.SASLMechanism,
 			kafkaParams.SASLUsername,
-			kafkaParams.SASLPassword,
-			kafkaParams.SASLHandshakeVersion))
+			kafkaParams.Password(),
+			kafkaParams.SASLHandshakeVersion,
+			kafkaParams.SASLOptions()...))
 
 	if err != nil {
 		return nil, nil, nil, err
@@ -68,6 +126,20 @@ func GetBrokers(commaSeparated string) []string {
 	return brokers
 }
 
+// IsFlagSet returns true if the flag has been explicitly set by the user, either on the command line or through
+// its environment variable.
+func IsFlagSet(ctx *kingpin.ParseContext, flag *kingpin.FlagClause) bool {
+	if flag == nil {
+		return false
+	}
+	for _, element := range ctx.Elements {
+		if element.Clause == flag {
+			return true
+		}
+	}
+	return flag.HasEnvarValue()
+}
+
 // AddFormatFlag adds the format flag to the specified command.
 func AddFormatFlag(c *kingpin.CmdClause, format *string, style *string) {
 	c.Flag("format", "Sets the output format.").
@@ -0,0 +1,139 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/config"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type add struct {
+	app          *kingpin.Application
+	globalParams *commands.GlobalParameters
+	profile      *config.Profile
+	use          bool
+	overwrite    bool
+}
+
+func addAddSubCommand(app *kingpin.Application, parent *kingpin.CmdClause, global *commands.GlobalParameters) {
+	cmd := &add{
+		app:          app,
+		globalParams: global,
+		profile:      &config.Profile{},
+	}
+	c := parent.Command("add", "Adds a new cluster profile to the configuration file, using the connection flags (eg. --brokers, --tls or --sasl-mechanism) which have been set explicitly.").Action(cmd.run)
+	c.Arg("name", "The name of the profile. The name will also be used as the default environment of the local offsets.").
+		Required().
+		StringVar(&cmd.profile.Name)
+	c.Flag("proto-root", "The default path to the folder where your *.proto files live.").
+		NoEnvar().
+		StringVar(&cmd.profile.ProtoRoot)
+	c.Flag("format", "The default output format of the consume commands. It will be ignored by the commands which do not support the format.").
+		NoEnvar().
+		EnumVar(&cmd.profile.Format,
+			internal.PlainTextEncoding,
+			internal.JSONEncoding,
+			internal.JSONIndentEncoding,
+			internal.Base64Encoding,
+			internal.HexEncoding)
+	c.Flag("use", "Sets the new profile as the current profile.").
+		NoEnvar().
+		BoolVar(&cmd.use)
+	c.Flag("overwrite", "Replaces the profile if it already exists.").
+		NoEnvar().
+		BoolVar(&cmd.overwrite)
+}
+
+func (a *add) run(ctx *kingpin.ParseContext) error {
+	cfg, err := config.Load(a.globalParams.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	a.profile.Brokers = a.value(ctx, "brokers")
+	a.profile.KafkaVersion = a.value(ctx, "kafka-version")
+
+	tls := config.TLS{
+		CACert:            a.value(ctx, "ca-cert"),
+		ClientCert:        a.value(ctx, "client-cert"),
+		ClientKey:         a.value(ctx, "client-key"),
+		PKCS12:            a.value(ctx, "tls-pkcs12"),
+		KeyPassword:       a.value(ctx, "tls-key-password"),
+		VerifySystemRoots: a.value(ctx, "tls-verify-system-roots") == "true",
+		ServerName:        a.value(ctx, "tls-server-name"),
+		MinVersion:        a.value(ctx, "tls-min-version"),
+		CipherSuites:      a.value(ctx, "tls-cipher-suites"),
+	}
+	if a.value(ctx, "tls") == "true" || tls != (config.TLS{}) {
+		// The paths must remain valid regardless of the working directory of the commands which use the profile.
+		for _, path := range []*string{&tls.CACert, &tls.ClientCert, &tls.ClientKey, &tls.PKCS12} {
+			if *path == "" {
+				continue
+			}
+			if *path, err = filepath.Abs(*path); err != nil {
+				return err
+			}
+		}
+		a.profile.TLS = &tls
+	}
+
+	mechanism := a.value(ctx, "sasl-mechanism")
+	if mechanism != "" && mechanism != kafka.SASLMechanismNone {
+		a.profile.SASL = &config.SASL{
+			Mechanism:       mechanism,
+			Username:        a.value(ctx, "sasl-username"),
+			Password:        a.value(ctx, "sasl-password"),
+			PasswordFile:    a.value(ctx, "sasl-password-file"),
+			PasswordCommand: a.value(ctx, "sasl-password-cmd"),
+			Version:         a.value(ctx, "sasl-version"),
+
+			OAuthTokenFile:      a.value(ctx, "sasl-oauth-token-file"),
+			OAuthTokenURL:       a.value(ctx, "sasl-oauth-token-url"),
+			OAuthScopes:         a.value(ctx, "sasl-oauth-scopes"),
+			KerberosConfig:      a.value(ctx, "sasl-kerberos-config"),
+			KerberosKeyTab:      a.value(ctx, "sasl-kerberos-keytab"),
+			KerberosServiceName: a.value(ctx, "sasl-kerberos-service-name"),
+			KerberosRealm:       a.value(ctx, "sasl-kerberos-realm"),
+		}
+		sasl := a.profile.SASL
+		for _, path := range []*string{&sasl.PasswordFile, &sasl.OAuthTokenFile, &sasl.KerberosConfig, &sasl.KerberosKeyTab} {
+			if *path == "" {
+				continue
+			}
+			if *path, err = filepath.Abs(*path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := cfg.Add(a.profile, a.overwrite); err != nil {
+		return err
+	}
+
+	if a.use || len(cfg.Profiles) == 1 {
+		if err := cfg.Use(a.profile.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("The %s profile has been saved to %s.\n", a.profile.Name, cfg.Path())
+	return nil
+}
+
+// value returns the value of the global flag if it has been explicitly set by the user.
+func (a *add) value(ctx *kingpin.ParseContext, name string) string {
+	flag := a.app.GetFlag(name)
+	if !commands.IsFlagSet(ctx, flag) {
+		return ""
+	}
+	return flag.Model().Value.String()
+}
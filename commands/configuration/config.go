@@ -0,0 +1,36 @@
+package configuration
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/config"
+)
+
+// AddCommands adds the config command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters) {
+	parent := app.Command("config", "A command to manage the named cluster profiles.")
+	addListSubCommand(parent, global)
+	addShowSubCommand(parent, global)
+	addAddSubCommand(app, parent, global)
+	addRemoveSubCommand(parent, global)
+	addUseSubCommand(parent, global)
+}
+
+const maskedPassword = "********"
+
+// mask returns a copy of the profile with the SASL and TLS key passwords masked.
+func mask(profile *config.Profile) *config.Profile {
+	masked := *profile
+	if profile.SASL != nil && profile.SASL.Password != "" {
+		sasl := *profile.SASL
+		sasl.Password = maskedPassword
+		masked.SASL = &sasl
+	}
+	if profile.TLS != nil && profile.TLS.KeyPassword != "" {
+		tls := *profile.TLS
+		tls.KeyPassword = maskedPassword
+		masked.TLS = &tls
+	}
+	return &masked
+}
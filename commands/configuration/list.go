@@ -0,0 +1,121 @@
+package configuration
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/config"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+)
+
+type listProfiles struct {
+	globalParams *commands.GlobalParameters
+	format       string
+	style        string
+}
+
+func addListSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters) {
+	cmd := &listProfiles{
+		globalParams: global,
+	}
+	c := parent.Command("list", "Lists the cluster profiles. The current profile is marked with *.").Action(cmd.run)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (l *listProfiles) run(_ *kingpin.ParseContext) error {
+	cfg, err := config.Load(l.globalParams.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Profiles) == 0 {
+		fmt.Printf("No profiles have been defined in %s. Use the config add command to add a new profile.\n", cfg.Path())
+		return nil
+	}
+
+	switch l.format {
+	case commands.JSONFormat:
+		type profile struct {
+			*config.Profile
+			Current bool `json:"current"`
+		}
+		data := make([]profile, 0, len(cfg.Profiles))
+		for _, name := range cfg.Names() {
+			data = append(data, profile{
+				Profile: mask(cfg.Profiles[name]),
+				Current: name == cfg.CurrentProfile,
+			})
+		}
+		return output.PrintAsJSON(data, l.style, l.globalParams.EnableColor)
+	case commands.TableFormat:
+		return l.printAsTable(cfg)
+	case commands.TreeFormat:
+		return l.printAsList(cfg, false)
+	case commands.PlainTextFormat:
+		return l.printAsList(cfg, true)
+	default:
+		return nil
+	}
+}
+
+func (l *listProfiles) printAsTable(cfg *config.Config) error {
+	table := tabular.NewTable(l.globalParams.EnableColor,
+		tabular.C("Name").Align(tabular.AlignLeft),
+		tabular.C("Brokers").Align(tabular.AlignLeft),
+		tabular.C("TLS"),
+		tabular.C("SASL"),
+		tabular.C("Proto Root").Align(tabular.AlignLeft),
+		tabular.C("Format"),
+	)
+	for _, name := range cfg.Names() {
+		profile := cfg.Profiles[name]
+		var title interface{} = name
+		if name == cfg.CurrentProfile {
+			title = format.BoldGreen("*"+name, l.globalParams.EnableColor)
+		}
+		tls := "No"
+		if profile.TLS != nil {
+			tls = "Yes"
+		}
+		table.AddRow(title,
+			profile.Brokers,
+			tls,
+			saslMechanism(profile),
+			format.SpaceIfEmpty(profile.ProtoRoot),
+			format.SpaceIfEmpty(profile.Format))
+	}
+	table.AddFooter(fmt.Sprintf("Total: %d", len(cfg.Profiles)), " ", " ", " ", " ", " ")
+	table.Render()
+	return nil
+}
+
+func (l *listProfiles) printAsList(cfg *config.Config, plain bool) error {
+	ls := list.New(plain)
+	for _, name := range cfg.Names() {
+		profile := cfg.Profiles[name]
+		if name == cfg.CurrentProfile {
+			ls.AddItem("*" + name)
+		} else {
+			ls.AddItem(name)
+		}
+		ls.Indent()
+		ls.AddItemF("Brokers: %s", profile.Brokers)
+		ls.AddItemF("TLS: %v", profile.TLS != nil)
+		ls.AddItemF("SASL: %s", saslMechanism(profile))
+		ls.UnIndent()
+	}
+	ls.Render()
+	return nil
+}
+
+func saslMechanism(profile *config.Profile) string {
+	if profile.SASL == nil || profile.SASL.Mechanism == "" {
+		return "none"
+	}
+	return profile.SASL.Mechanism
+}
@@ -0,0 +1,46 @@
+package configuration
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/config"
+)
+
+type remove struct {
+	globalParams *commands.GlobalParameters
+	name         string
+	silent       bool
+}
+
+func addRemoveSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters) {
+	cmd := &remove{
+		globalParams: global,
+	}
+	c := parent.Command("remove", "Removes a profile from the configuration file.").Action(cmd.run)
+	c.Arg("name", "The name of the profile to remove.").Required().StringVar(&cmd.name)
+	c.Flag("silent", "Removes the profile without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+}
+
+func (r *remove) run(_ *kingpin.ParseContext) error {
+	cfg, err := config.Load(r.globalParams.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Remove(r.name); err != nil {
+		return err
+	}
+	if !r.silent && !commands.AskForConfirmation(fmt.Sprintf("The %s profile will be removed. Are you sure", r.name)) {
+		return nil
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("The %s profile has been removed.\n", r.name)
+	return nil
+}
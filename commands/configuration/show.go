@@ -0,0 +1,48 @@
+package configuration
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/config"
+)
+
+type show struct {
+	globalParams *commands.GlobalParameters
+	name         string
+}
+
+func addShowSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters) {
+	cmd := &show{
+		globalParams: global,
+	}
+	c := parent.Command("show", "Prints the settings of a cluster profile. The SASL and TLS key passwords will be masked.").Action(cmd.run)
+	c.Arg("name", "The name of the profile (Default: the current profile).").StringVar(&cmd.name)
+}
+
+func (s *show) run(_ *kingpin.ParseContext) error {
+	cfg, err := config.Load(s.globalParams.ConfigFile)
+	if err != nil {
+		return err
+	}
+	profile, err := cfg.Profile(s.name)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return fmt.Errorf("no current profile has been set in %s. Specify the profile name or use the config use command", cfg.Path())
+	}
+	content, err := yaml.Marshal(mask(profile))
+	if err != nil {
+		return err
+	}
+	current := ""
+	if profile.Name == cfg.CurrentProfile {
+		current = " (current)"
+	}
+	fmt.Printf("%s%s\n\n%s", profile.Name, current, content)
+	return nil
+}
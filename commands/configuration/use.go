@@ -0,0 +1,38 @@
+package configuration
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/config"
+)
+
+type use struct {
+	globalParams *commands.GlobalParameters
+	name         string
+}
+
+func addUseSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters) {
+	cmd := &use{
+		globalParams: global,
+	}
+	c := parent.Command("use", "Sets the current profile, which will be used when no profile has been selected using --profile.").Action(cmd.run)
+	c.Arg("name", "The name of the profile.").Required().StringVar(&cmd.name)
+}
+
+func (u *use) run(_ *kingpin.ParseContext) error {
+	cfg, err := config.Load(u.globalParams.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Use(u.name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Switched to the %s profile.\n", u.name)
+	return nil
+}
@@ -15,7 +15,7 @@ import (
 
 	"github.com/xitonix/trubka/commands"
 	"github.com/xitonix/trubka/internal"
-	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/filter"
 	"github.com/xitonix/trubka/kafka"
 )
 
@@ -24,16 +24,19 @@ func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, ka
 	parent := app.Command("consume", "A command to consume events from Kafka.")
 	addConsumeProtoCommand(parent, global, kafkaParams)
 	addConsumePlainCommand(parent, global, kafkaParams)
+	addConsumeRegistryCommand(parent, global, kafkaParams)
 }
 
 func bindCommonConsumeFlags(command *kingpin.CmdClause,
-	topic, environment, outputDir, logFile *string,
+	topic, environment, group, outputDir, logFile *string,
 	from, to *[]string,
 	exclusive *bool,
 	idleTimeout *time.Duration,
 	inclusions *internal.MessageMetadata,
 	enableAutoTopicCreation, reverse, interactive, interactiveWithCustomOffset, count *bool,
-	searchQuery, topicFilter **regexp.Regexp, highlightStyle *string) {
+	searchQuery, topicFilter **regexp.Regexp, highlightStyle, isolation *string,
+	where **filter.Expression,
+	selector **filter.Selector) {
 
 	command.Arg("topic", "The Kafka topic to consume from.").StringVar(topic)
 
@@ -57,14 +60,22 @@ func bindCommonConsumeFlags(command *kingpin.CmdClause,
 		Short('S').
 		BoolVar(&inclusions.Timestamp)
 
+	command.Flag("include-headers", "Prints the message headers if they have been provided by Kafka.").
+		Short('H').
+		BoolVar(&inclusions.Headers)
+
 	command.Flag("auto-topic-creation", `Enables automatic topic creation before consuming if it's allowed by the server.`).
 		BoolVar(enableAutoTopicCreation)
 
-	command.Flag("environment", `To store the offsets on the disk in environment specific paths. It's only required if you use Trubka to consume from different Kafka clusters on the same machine (eg. dev/prod).`).
+	command.Flag("environment", `To store the offsets on the disk in environment specific paths. It's only required if you use Trubka to consume from different Kafka clusters on the same machine (eg. dev/prod). Defaults to the name of the active profile if one has been selected.`).
 		Short('e').
 		Default("local").
 		StringVar(environment)
 
+	command.Flag("group", `Joins the consumer group and commits the offsets to Kafka instead of the local offset store. The partitions will be shared between all the members of the group. Only newest and oldest start offsets are supported in this mode, which will be used if the group has no committed offsets.`).
+		Short('G').
+		StringVar(group)
+
 	command.Flag("search-query", "The optional regular expression to filter the message content by.").
 		Short('q').
 		RegexpVar(searchQuery)
@@ -72,6 +83,14 @@ func bindCommonConsumeFlags(command *kingpin.CmdClause,
 	command.Flag("reverse", "If set, the messages which match the --search-query will be filtered out.").
 		BoolVar(reverse)
 
+	command.Flag("where", `The optional expression to filter the messages by the fields of the decoded Json or protobuf content (eg. 'user.id == 42 && status in ["FAILED", "RETRY"]'). Supported operators are ==, !=, <, <=, >, >=, in, =~ (regex), &&, || and !. The messages which are not valid Json will be reported as failures.`).
+		PlaceHolder("EXPRESSION").
+		SetValue(&expressionValue{target: where})
+
+	command.Flag("select", `The optional jq-like selector to extract or reshape the fields of the decoded Json or protobuf content before writing to the output (eg. '.user.name' or '{id: .user.id, status, prices: .items[].price}'). The metadata inclusions (-P, -K, -T, -O, -S and -H) will wrap the selected content.`).
+		PlaceHolder("SELECTOR").
+		SetValue(&selectorValue{target: selector})
+
 	command.Flag("output-dir", "The `directory` to write the Kafka messages to (Default: Stdout).").
 		Short('d').
 		StringVar(outputDir)
@@ -92,10 +111,14 @@ func bindCommonConsumeFlags(command *kingpin.CmdClause,
 		Short('t').
 		RegexpVar(topicFilter)
 
-	command.Flag("count", "Count the number of messages consumed from Kafka.").
+	command.Flag("count", "Count the number of messages consumed from Kafka. The offsets which have not been delivered to the consumer, such as the transaction markers and the messages of the aborted transactions, will be reported as skipped.").
 		Short('c').
 		BoolVar(count)
 
+	command.Flag("isolation", fmt.Sprintf("The isolation level of the consumer. Set to %s to only consume the messages of the committed transactions.", kafka.ReadCommitted)).
+		Default(kafka.ReadUncommitted).
+		EnumVar(isolation, kafka.IsolationLevels...)
+
 	now := time.Now()
 
 	ts := internal.FormatTime(now.Add(-2 * time.Hour))
@@ -140,12 +163,63 @@ func monitorCancellation(prn *internal.SyncPrinter, cancel context.CancelFunc) {
 	cancel()
 }
 
+// expressionValue parses the filter expression flags.
+type expressionValue struct {
+	target **filter.Expression
+}
+
+func (e *expressionValue) Set(value string) error {
+	expression, err := filter.Parse(value)
+	if err != nil {
+		return err
+	}
+	*e.target = expression
+	return nil
+}
+
+func (e *expressionValue) String() string {
+	if *e.target == nil {
+		return ""
+	}
+	return (*e.target).String()
+}
+
+// selectorValue parses the field selector flags.
+type selectorValue struct {
+	target **filter.Selector
+}
+
+func (s *selectorValue) Set(value string) error {
+	selector, err := filter.ParseSelector(value)
+	if err != nil {
+		return err
+	}
+	*s.target = selector
+	return nil
+}
+
+func (s *selectorValue) String() string {
+	if *s.target == nil {
+		return ""
+	}
+	return (*s.target).String()
+}
+
+func validateSelector(selector *filter.Selector, format string) error {
+	if selector != nil && (format == internal.Base64Encoding || format == internal.HexEncoding) {
+		return fmt.Errorf("--select cannot be used with the %s output format", format)
+	}
+	return nil
+}
+
 func initialiseConsumer(kafkaParams *commands.KafkaParameters,
 	globalParams *commands.GlobalParameters,
 	environment string,
+	group string,
 	enableAutoTopicCreation bool,
 	exclusive bool,
 	idleTimeout time.Duration,
+	isolation string,
 	logFile io.Writer,
 	printer internal.Printer) (*kafka.Consumer, error) {
 	saramaLogWriter := io.Discard
@@ -155,9 +229,9 @@ func initialiseConsumer(kafkaParams *commands.KafkaParameters,
 
 	brokers := commands.GetBrokers(kafkaParams.Brokers)
 
-	store, err := kafka.NewLocalOffsetStore(printer, environment)
-	if err != nil {
-		return nil, err
+	groupMode := !internal.IsEmpty(group)
+	if groupMode && idleTimeout > 0 {
+		return nil, errors.New("--idle-timeout is not supported in consumer group mode")
 	}
 
 	wrapper, err := kafka.NewConsumerWrapper(brokers, kafka.WithClusterVersion(kafkaParams.Version),
@@ -165,9 +239,20 @@ func initialiseConsumer(kafkaParams *commands.KafkaParameters,
 		kafka.WithLogWriter(saramaLogWriter),
 		kafka.WithSASL(kafkaParams.SASLMechanism,
 			kafkaParams.SASLUsername,
-			kafkaParams.SASLPassword,
-			kafkaParams.SASLHandshakeVersion))
+			kafkaParams.Password(),
+			kafkaParams.SASLHandshakeVersion,
+			kafkaParams.SASLOptions()...),
+		kafka.WithIsolationLevel(isolation))
+
+	if err != nil {
+		return nil, err
+	}
 
+	if groupMode {
+		return kafka.NewGroupConsumer(strings.TrimSpace(group), wrapper, printer, enableAutoTopicCreation), nil
+	}
+
+	store, err := kafka.NewLocalOffsetStore(printer, environment)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +269,90 @@ func initialiseConsumer(kafkaParams *commands.KafkaParameters,
 	return consumer, nil
 }
 
+// processFunc converts the consumed event to the content to be written to the output.
+//
+// The event will be skipped if the returned content is nil.
+type processFunc func(event *kafka.Event) ([]byte, error)
+
+// consumeTopics writes the events of the topics to the output, until the consumer stops or ctx gets cancelled.
+//
+// newProcessor is called once the output writers have been initialised, to create the function which processes the events.
+func consumeTopics(ctx context.Context,
+	consumer *kafka.Consumer,
+	prn *internal.SyncPrinter,
+	topics map[string]*kafka.PartitionCheckpoints,
+	outputDir string,
+	logFile io.Writer,
+	writeLogToFile bool,
+	count bool,
+	enableColor bool,
+	newProcessor func(writeEventsToFile bool) processFunc) error {
+
+	writers, writeEventsToFile, err := getOutputWriters(outputDir, topics)
+	if err != nil {
+		return err
+	}
+
+	prn.Start(writers)
+
+	counter := internal.NewCounter()
+
+	if len(topics) > 0 {
+		process := newProcessor(writeEventsToFile)
+		err = commands.ConsumeEvents(ctx, consumer, prn, topics, func(event *kafka.Event) (bool, error) {
+			if count {
+				counter.IncrSkipped(event.Topic, event.Skipped)
+			}
+
+			output, err := process(event)
+			if err == nil {
+				prn.WriteEvent(event.Topic, output)
+				if count {
+					counter.IncrSuccess(event.Topic)
+				}
+				return true, nil
+			}
+
+			if count {
+				counter.IncrFailure(event.Topic)
+			}
+			prn.Errorf(internal.Forced,
+				"Failed to process the message at offset %d of partition %d, topic %s: %s",
+				event.Offset,
+				event.Partition,
+				event.Topic,
+				err)
+			return false, nil
+		})
+	} else {
+		prn.Warning(internal.Forced, "Nothing to process. Terminating Trubka.")
+		consumer.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Do not write to Printer after this point
+	if writeLogToFile {
+		prn.Info(internal.SuperVerbose, "Closing the log file")
+		commands.CloseFile(logFile.(*os.File), enableColor)
+	}
+
+	if writeEventsToFile {
+		prn.Info(internal.SuperVerbose, "Closing the output files")
+		for _, w := range writers {
+			commands.CloseFile(w.(*os.File), enableColor)
+		}
+	}
+	prn.Close()
+
+	if count {
+		counter.PrintAsTable(enableColor)
+	}
+	return nil
+}
+
 func getOutputWriters(outputDir string, topics map[string]*kafka.PartitionCheckpoints) (map[string]io.Writer, bool, error) {
 	result := make(map[string]io.Writer)
 
@@ -200,10 +369,9 @@ func getOutputWriters(outputDir string, topics map[string]*kafka.PartitionCheckp
 	}
 
 	for topic := range topics {
-		file := filepath.Join(outputDir, topic)
-		lf, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+		lf, err := commands.CreateFile(filepath.Join(outputDir, topic))
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to create %s: %w", file, err)
+			return nil, false, err
 		}
 		result[topic] = lf
 	}
@@ -218,9 +386,9 @@ func getLogWriter(logFile string) (io.Writer, bool, error) {
 	case "":
 		return os.Stderr, false, nil
 	default:
-		lf, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+		lf, err := commands.CreateFile(logFile)
 		if err != nil {
-			return nil, false, fmt.Errorf("failed to create %s: %w", logFile, err)
+			return nil, false, err
 		}
 		return lf, true, nil
 	}
@@ -240,15 +408,3 @@ func filterError(err error) error {
 	}
 	return err
 }
-
-func closeFile(file *os.File, highlight bool) {
-	err := file.Sync()
-	if err != nil {
-		msg := fmt.Sprintf("Failed to sync the file: %s", err)
-		fmt.Println(format.Red(msg, highlight))
-	}
-	if err := file.Close(); err != nil {
-		msg := fmt.Sprintf("Failed to close the file: %s", err)
-		fmt.Println(format.Red(msg, highlight))
-	}
-}
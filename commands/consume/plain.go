@@ -5,15 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
-	"sync"
 	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/xitonix/trubka/commands"
 	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/filter"
 	"github.com/xitonix/trubka/internal/output/format"
 	"github.com/xitonix/trubka/kafka"
 )
@@ -27,8 +26,11 @@ type consumePlain struct {
 	encodeTo                string
 	outputDir               string
 	environment             string
+	group                   string
 	logFile                 string
 	searchQuery             *regexp.Regexp
+	where                   *filter.Expression
+	selector                *filter.Selector
 	topicFilter             *regexp.Regexp
 	interactive             bool
 	interactiveWithOffset   bool
@@ -41,6 +43,7 @@ type consumePlain struct {
 	idleTimeout             time.Duration
 	count                   bool
 	highlightStyle          string
+	isolation               string
 }
 
 func addConsumePlainCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
@@ -54,6 +57,7 @@ func addConsumePlainCommand(parent *kingpin.CmdClause, global *commands.GlobalPa
 	bindCommonConsumeFlags(c,
 		&cmd.topic,
 		&cmd.environment,
+		&cmd.group,
 		&cmd.outputDir,
 		&cmd.logFile,
 		&cmd.from,
@@ -68,7 +72,10 @@ func addConsumePlainCommand(parent *kingpin.CmdClause, global *commands.GlobalPa
 		&cmd.count,
 		&cmd.searchQuery,
 		&cmd.topicFilter,
-		&cmd.highlightStyle)
+		&cmd.highlightStyle,
+		&cmd.isolation,
+		&cmd.where,
+		&cmd.selector)
 
 	c.Flag("decode-from", "The encoding of the incoming message content.").
 		Short('D').
@@ -92,6 +99,10 @@ func (c *consumePlain) run(_ *kingpin.ParseContext) error {
 		return errors.New("which Kafka topic you would like to consume from? Make sure you provide the topic as the first argument or switch to interactive mode (-i/-I)")
 	}
 
+	if err := validateSelector(c.selector, c.encodeTo); err != nil {
+		return err
+	}
+
 	logFile, writeLogToFile, err := getLogWriter(c.logFile)
 	if err != nil {
 		return err
@@ -103,9 +114,11 @@ func (c *consumePlain) run(_ *kingpin.ParseContext) error {
 		c.kafkaParams,
 		c.globalParams,
 		c.environment,
+		c.group,
 		c.enableAutoTopicCreation,
 		c.exclusive,
 		c.idleTimeout,
+		c.isolation,
 		logFile,
 		prn)
 	if err != nil {
@@ -136,102 +149,48 @@ func (c *consumePlain) run(_ *kingpin.ParseContext) error {
 		topics[c.topic] = checkpoints
 	}
 
-	writers, writeEventsToFile, err := getOutputWriters(c.outputDir, topics)
-	if err != nil {
-		return err
-	}
-
-	prn.Start(writers)
-
-	wg := sync.WaitGroup{}
-
-	wg.Add(1)
-	consumerCtx, stopConsumer := context.WithCancel(context.Background())
-	defer stopConsumer()
-	counter := internal.NewCounter()
-
-	go func() {
-		defer wg.Done()
-		c.inclusions.Topic = c.inclusions.Topic && !writeEventsToFile
-		c.inclusions.SetIndentation()
-		marshaller := internal.NewPlainTextMarshaller(
-			c.decodeFrom,
-			c.encodeTo,
-			c.inclusions,
-			c.globalParams.EnableColor && !writeEventsToFile,
-			c.highlightStyle)
-
-		var cancelled bool
-		for {
-			select {
-			case <-ctx.Done():
-				if !cancelled {
-					stopConsumer()
-					cancelled = true
-				}
-			case event, more := <-consumer.Events():
-				if !more {
-					consumer.CloseOffsetStore()
-					return
-				}
-
-				output, err := c.process(event, marshaller, c.globalParams.EnableColor && !writeEventsToFile)
-				if err == nil {
-					prn.WriteEvent(event.Topic, output)
-					consumer.StoreOffset(event)
-					if c.count {
-						counter.IncrSuccess(event.Topic)
-					}
-					continue
-				}
-				if c.count {
-					counter.IncrFailure(event.Topic)
-				}
-				prn.Errorf(internal.Forced,
-					"Failed to process the message at offset %d of partition %d, topic %s: %s",
-					event.Offset,
-					event.Partition,
-					event.Topic,
-					err)
+	return consumeTopics(ctx,
+		consumer,
+		prn,
+		topics,
+		c.outputDir,
+		logFile,
+		writeLogToFile,
+		c.count,
+		c.globalParams.EnableColor,
+		func(writeEventsToFile bool) processFunc {
+			c.inclusions.Topic = c.inclusions.Topic && !writeEventsToFile
+			c.inclusions.SetIndentation()
+			highlight := c.globalParams.EnableColor && !writeEventsToFile
+			marshaller := internal.NewPlainTextMarshaller(
+				c.decodeFrom,
+				c.encodeTo,
+				c.inclusions,
+				highlight,
+				c.highlightStyle,
+				c.selector)
+			return func(event *kafka.Event) ([]byte, error) {
+				return c.process(event, marshaller, highlight)
 			}
-		}
-	}()
-	err = consumer.Start(consumerCtx, topics)
-	if err != nil {
-		prn.Errorf(internal.Forced, "Failed to start the consumer: %s", err)
-	}
-
-	// We still need to explicitly close the underlying Kafka client, in case `consumer.Start` has not been called.
-	// It is safe to close the consumer twice.
-	consumer.Close()
-	wg.Wait()
-
-	if err != nil {
-		return err
-	}
-
-	// Do not write to Printer after this point
-	if writeLogToFile {
-		closeFile(logFile.(*os.File), c.globalParams.EnableColor)
-	}
+		})
+}
 
-	if writeEventsToFile {
-		for _, w := range writers {
-			closeFile(w.(*os.File), c.globalParams.EnableColor)
+func (c *consumePlain) process(event *kafka.Event, marshaller *internal.PlainTextMarshaller, highlight bool) ([]byte, error) {
+	if c.where != nil {
+		decoded, err := marshaller.Decode(event.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' message received from Kafka: %w", c.decodeFrom, err)
+		}
+		matched, err := c.where.MatchJSON(decoded)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return nil, nil
 		}
 	}
 
-	prn.Close()
-
-	if c.count {
-		counter.PrintAsTable(c.globalParams.EnableColor)
-	}
-
-	return nil
-}
-
-func (c *consumePlain) process(event *kafka.Event, marshaller *internal.PlainTextMarshaller, highlight bool) ([]byte, error) {
-	output, err := marshaller.Marshal(event.Value, event.Key, event.Timestamp, event.Topic, event.Partition, event.Offset)
+	output, err := marshaller.Marshal(event.Value, event.Key, event.Timestamp, event.Topic, event.Partition, event.Offset, event.Headers)
 	if err != nil {
 		return nil, fmt.Errorf("invalid '%s' message received from Kafka: %w", c.decodeFrom, err)
 	}
@@ -5,15 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"regexp"
-	"sync"
 	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/xitonix/trubka/commands"
 	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/filter"
 	"github.com/xitonix/trubka/internal/output/format"
 	"github.com/xitonix/trubka/kafka"
 	"github.com/xitonix/trubka/protobuf"
@@ -29,10 +28,13 @@ type consumeProto struct {
 	encodeTo                string
 	outputDir               string
 	environment             string
+	group                   string
 	logFile                 string
 	topicFilter             *regexp.Regexp
 	protoFilter             *regexp.Regexp
 	searchQuery             *regexp.Regexp
+	where                   *filter.Expression
+	selector                *filter.Selector
 	interactive             bool
 	interactiveWithOffset   bool
 	reverse                 bool
@@ -44,6 +46,7 @@ type consumeProto struct {
 	exclusive               bool
 	idleTimeout             time.Duration
 	highlightStyle          string
+	isolation               string
 }
 
 func addConsumeProtoCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
@@ -56,6 +59,7 @@ func addConsumeProtoCommand(parent *kingpin.CmdClause, global *commands.GlobalPa
 	bindCommonConsumeFlags(c,
 		&cmd.topic,
 		&cmd.environment,
+		&cmd.group,
 		&cmd.outputDir,
 		&cmd.logFile,
 		&cmd.from,
@@ -70,7 +74,10 @@ func addConsumeProtoCommand(parent *kingpin.CmdClause, global *commands.GlobalPa
 		&cmd.count,
 		&cmd.searchQuery,
 		&cmd.topicFilter,
-		&cmd.highlightStyle)
+		&cmd.highlightStyle,
+		&cmd.isolation,
+		&cmd.where,
+		&cmd.selector)
 
 	cmd.bindCommandFlags(c)
 }
@@ -78,9 +85,8 @@ func addConsumeProtoCommand(parent *kingpin.CmdClause, global *commands.GlobalPa
 func (c *consumeProto) bindCommandFlags(command *kingpin.CmdClause) {
 	command.Arg("contract", "The fully qualified name of the protocol buffers type, stored in the given topic. The default value is the same as the topic name.").
 		StringVar(&c.messageType)
-	command.Flag("proto-root", "The path to the folder where your *.proto files live.").
+	command.Flag("proto-root", "The path to the folder where your *.proto files live (Default: the proto root of the active profile).").
 		Short('r').
-		Required().
 		StringVar(&c.protoRoot)
 
 	command.Flag("proto-filter", "The optional regular expression to filter the proto types by (Interactive mode only).").
@@ -98,6 +104,14 @@ func (c *consumeProto) bindCommandFlags(command *kingpin.CmdClause) {
 }
 
 func (c *consumeProto) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(c.protoRoot) {
+		return commands.ErrMissingProtoRoot
+	}
+
+	if err := validateSelector(c.selector, c.encodeTo); err != nil {
+		return err
+	}
+
 	interactive := c.interactive || c.interactiveWithOffset
 	var implicitContract bool
 	if !interactive {
@@ -121,9 +135,11 @@ func (c *consumeProto) run(_ *kingpin.ParseContext) error {
 		c.kafkaParams,
 		c.globalParams,
 		c.environment,
+		c.group,
 		c.enableAutoTopicCreation,
 		c.exclusive,
 		c.idleTimeout,
+		c.isolation,
 		logFile,
 		prn)
 
@@ -174,102 +190,28 @@ func (c *consumeProto) run(_ *kingpin.ParseContext) error {
 		}
 	}
 
-	writers, writeEventsToFile, err := getOutputWriters(c.outputDir, topics)
-	if err != nil {
-		return err
-	}
-
-	prn.Start(writers)
-
-	wg := sync.WaitGroup{}
-
-	counter := internal.NewCounter()
-
-	if len(tm) > 0 {
-		wg.Add(1)
-		consumerCtx, stopConsumer := context.WithCancel(context.Background())
-		defer stopConsumer()
-		go func() {
-			defer wg.Done()
-
+	return consumeTopics(ctx,
+		consumer,
+		prn,
+		topics,
+		c.outputDir,
+		logFile,
+		writeLogToFile,
+		c.count,
+		c.globalParams.EnableColor,
+		func(writeEventsToFile bool) processFunc {
 			c.inclusions.Topic = c.inclusions.Topic && !writeEventsToFile
 			c.inclusions.SetIndentation()
+			highlight := c.globalParams.EnableColor && !writeEventsToFile
 			marshaller := protobuf.NewMarshaller(c.encodeTo,
 				c.inclusions,
-				c.globalParams.EnableColor && !writeEventsToFile,
-				c.highlightStyle)
-
-			var cancelled bool
-			for {
-				select {
-				case <-ctx.Done():
-					if !cancelled {
-						stopConsumer()
-						cancelled = true
-					}
-				case event, more := <-consumer.Events():
-					if !more {
-						consumer.CloseOffsetStore()
-						return
-					}
-
-					output, err := c.process(tm[event.Topic], loader, event, marshaller, c.globalParams.EnableColor && !writeEventsToFile)
-					if err == nil {
-						prn.WriteEvent(event.Topic, output)
-						consumer.StoreOffset(event)
-						if c.count {
-							counter.IncrSuccess(event.Topic)
-						}
-						continue
-					}
-
-					if c.count {
-						counter.IncrFailure(event.Topic)
-					}
-					prn.Errorf(internal.Forced,
-						"Failed to process the message at offset %d of partition %d, topic %s: %s",
-						event.Offset,
-						event.Partition,
-						event.Topic,
-						err)
-				}
+				highlight,
+				c.highlightStyle,
+				c.selector)
+			return func(event *kafka.Event) ([]byte, error) {
+				return c.process(tm[event.Topic], loader, event, marshaller, highlight)
 			}
-		}()
-		err = consumer.Start(consumerCtx, topics)
-		if err != nil {
-			prn.Errorf(internal.Forced, "Failed to start the consumer: %s", err)
-		}
-	} else {
-		prn.Warning(internal.Forced, "Nothing to process. Terminating Trubka.")
-	}
-
-	// We still need to explicitly close the underlying Kafka client, in case `consumer.Start` has not been called.
-	// It is safe to close the consumer twice.
-	consumer.Close()
-	wg.Wait()
-
-	if err != nil {
-		return err
-	}
-
-	// Do not write to Printer after this point
-	if writeLogToFile {
-		prn.Infof(internal.SuperVerbose, "Closing the log file %s", c.logFile)
-		closeFile(logFile.(*os.File), c.globalParams.EnableColor)
-	}
-
-	if writeEventsToFile {
-		prn.Info(internal.SuperVerbose, "Closing the output files")
-		for _, w := range writers {
-			closeFile(w.(*os.File), c.globalParams.EnableColor)
-		}
-	}
-	prn.Close()
-
-	if c.count {
-		counter.PrintAsTable(c.globalParams.EnableColor)
-	}
-	return nil
+		})
 }
 
 func (c *consumeProto) process(messageType string,
@@ -288,7 +230,11 @@ func (c *consumeProto) process(messageType string,
 		return nil, err
 	}
 
-	output, err := marshaller.Marshal(msg, event.Key, event.Timestamp, event.Topic, event.Partition, event.Offset)
+	if c.where != nil && !c.where.Match(protobuf.ToMap(msg)) {
+		return nil, nil
+	}
+
+	output, err := marshaller.Marshal(msg, event.Key, event.Timestamp, event.Topic, event.Partition, event.Offset, event.Headers)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,214 @@
+package consume
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/filter"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/kafka"
+	"github.com/xitonix/trubka/schemaregistry"
+)
+
+type consumeRegistry struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+
+	registryParams          *commands.RegistryParameters
+	topic                   string
+	encodeTo                string
+	outputDir               string
+	environment             string
+	group                   string
+	logFile                 string
+	searchQuery             *regexp.Regexp
+	where                   *filter.Expression
+	selector                *filter.Selector
+	topicFilter             *regexp.Regexp
+	interactive             bool
+	interactiveWithOffset   bool
+	reverse                 bool
+	inclusions              *internal.MessageMetadata
+	enableAutoTopicCreation bool
+	from                    []string
+	to                      []string
+	exclusive               bool
+	idleTimeout             time.Duration
+	count                   bool
+	highlightStyle          string
+	isolation               string
+}
+
+func addConsumeRegistryCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &consumeRegistry{
+		globalParams:   global,
+		kafkaParams:    kafkaParams,
+		registryParams: &commands.RegistryParameters{},
+		inclusions:     &internal.MessageMetadata{},
+	}
+	c := parent.Command("registry", "Starts consuming Avro, Protobuf or Json Schema events, serialised using the Schema Registry wire format.").Action(cmd.run)
+
+	bindCommonConsumeFlags(c,
+		&cmd.topic,
+		&cmd.environment,
+		&cmd.group,
+		&cmd.outputDir,
+		&cmd.logFile,
+		&cmd.from,
+		&cmd.to,
+		&cmd.exclusive,
+		&cmd.idleTimeout,
+		cmd.inclusions,
+		&cmd.enableAutoTopicCreation,
+		&cmd.reverse,
+		&cmd.interactive,
+		&cmd.interactiveWithOffset,
+		&cmd.count,
+		&cmd.searchQuery,
+		&cmd.topicFilter,
+		&cmd.highlightStyle,
+		&cmd.isolation,
+		&cmd.where,
+		&cmd.selector)
+
+	commands.BindRegistryFlags(c, cmd.registryParams)
+
+	c.Flag("format", "The format in which the incoming Kafka messages will be written to the output.").
+		Default(internal.JSONEncoding).
+		Short('f').
+		EnumVar(&cmd.encodeTo,
+			internal.JSONEncoding,
+			internal.JSONIndentEncoding)
+}
+
+func (c *consumeRegistry) run(_ *kingpin.ParseContext) error {
+	interactive := c.interactive || c.interactiveWithOffset
+	if !interactive && internal.IsEmpty(c.topic) {
+		return errors.New("which Kafka topic you would like to consume from? Make sure you provide the topic as the first argument or switch to interactive mode (-i/-I)")
+	}
+
+	logFile, writeLogToFile, err := getLogWriter(c.logFile)
+	if err != nil {
+		return err
+	}
+
+	prn := internal.NewPrinter(c.globalParams.Verbosity, logFile)
+
+	registry, err := commands.NewRegistryClient(c.registryParams)
+	if err != nil {
+		return err
+	}
+	serde := schemaregistry.NewSerde(registry)
+
+	consumer, err := initialiseConsumer(
+		c.kafkaParams,
+		c.globalParams,
+		c.environment,
+		c.group,
+		c.enableAutoTopicCreation,
+		c.exclusive,
+		c.idleTimeout,
+		c.isolation,
+		logFile,
+		prn)
+	if err != nil {
+		return err
+	}
+
+	// It is safe to close the consumer more than once.
+	defer consumer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go monitorCancellation(prn, cancel)
+
+	checkpoints, err := kafka.NewPartitionCheckpoints(c.from, c.to, c.exclusive)
+	if err != nil {
+		return err
+	}
+
+	topics := make(map[string]*kafka.PartitionCheckpoints)
+
+	if interactive {
+		topics, err = askUserForTopics(consumer, c.topic, c.topicFilter, c.interactiveWithOffset, checkpoints, c.exclusive)
+		if err != nil {
+			return filterError(err)
+		}
+	} else {
+		topics[c.topic] = checkpoints
+	}
+
+	return consumeTopics(ctx,
+		consumer,
+		prn,
+		topics,
+		c.outputDir,
+		logFile,
+		writeLogToFile,
+		c.count,
+		c.globalParams.EnableColor,
+		func(writeEventsToFile bool) processFunc {
+			c.inclusions.Topic = c.inclusions.Topic && !writeEventsToFile
+			c.inclusions.SetIndentation()
+			highlight := c.globalParams.EnableColor && !writeEventsToFile
+			marshaller := internal.NewPlainTextMarshaller(
+				internal.PlainTextEncoding,
+				c.encodeTo,
+				c.inclusions,
+				highlight,
+				c.highlightStyle,
+				c.selector)
+			return func(event *kafka.Event) ([]byte, error) {
+				return c.process(event, serde, marshaller, highlight)
+			}
+		})
+}
+
+func (c *consumeRegistry) process(event *kafka.Event,
+	serde *schemaregistry.Serde,
+	marshaller *internal.PlainTextMarshaller,
+	highlight bool) ([]byte, error) {
+	// The schema lookups are bound by the registry timeout, so that the in-flight events can still be
+	// processed after the consumer has been cancelled.
+	message, err := serde.Decode(context.Background(), event.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.where != nil {
+		matched, err := c.where.MatchJSON(message)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return nil, nil
+		}
+	}
+
+	output, err := marshaller.Marshal(message, event.Key, event.Timestamp, event.Topic, event.Partition, event.Offset, event.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.searchQuery != nil {
+		matches := c.searchQuery.FindAll(output, -1)
+		if (matches != nil) == c.reverse {
+			return nil, nil
+		}
+		for _, match := range matches {
+			if highlight {
+				output = bytes.ReplaceAll(output, match, []byte(fmt.Sprint(format.Yellow(string(match), true))))
+			}
+		}
+	}
+	return output, nil
+}
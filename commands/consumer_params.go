@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
+)
+
+// ConsumerParameters holds the CLI parameters to consume a range of messages using the local offset store.
+type ConsumerParameters struct {
+	// Environment the environment in which the local offsets are stored.
+	Environment string
+	// From the offsets to start consuming from.
+	From []string
+	// To the offsets where the consumer must stop.
+	To []string
+	// Exclusive only the explicitly defined partitions will be consumed if set.
+	Exclusive bool
+	// IdleTimeout the amount of time the consumer will wait for a message to arrive before stop consuming from a partition.
+	IdleTimeout time.Duration
+}
+
+// BindConsumerFlags binds the consumer range flags to the command.
+func BindConsumerFlags(c *kingpin.CmdClause, params *ConsumerParameters, defaultFrom, defaultIdleTimeout string) {
+	now := time.Now()
+	ts := internal.FormatTime(now.Add(-2 * time.Hour))
+	c.Flag("from", fmt.Sprintf("The offset to start consuming from. "+
+		"Available options are newest, oldest, local, timestamp, offset, Partition#Offset or Partition#Timestamp (eg. \"10#300\", \"7#%s\")", ts)).
+		Default(defaultFrom).
+		HintOptions("newest", "oldest", ts, "8000").
+		NoEnvar().
+		StringsVar(&params.From)
+
+	ts = internal.FormatTime(now.Add(-1 * time.Hour))
+	c.Flag("to", fmt.Sprintf("The offset where trubka must stop consuming. "+
+		"Available options are timestamp, offset, Partition#Offset or Partition#Timestamp (eg. \"10#800\", \"7#%s\")", ts)).
+		HintOptions(ts, "9000").
+		NoEnvar().
+		StringsVar(&params.To)
+
+	c.Flag("exclusive", `Only explicitly defined partitions (Partition#Offset or Partition#Timestamp) will be consumed. The rest will be excluded.`).
+		Short('E').
+		NoEnvar().
+		BoolVar(&params.Exclusive)
+
+	minTimeout := 2 * time.Second
+	help := fmt.Sprintf(`The amount of time the consumer will wait for a message to arrive before stop consuming from a partition (Minimum: %s)`, minTimeout)
+	if defaultIdleTimeout == "" || defaultIdleTimeout == "0" {
+		help += ". The consumer will not stop if not specified."
+	}
+	c.Flag("idle-timeout", help).
+		Default(defaultIdleTimeout).
+		PreAction(func(parseContext *kingpin.ParseContext) error {
+			if params.IdleTimeout < minTimeout {
+				params.IdleTimeout = minTimeout
+			}
+			return nil
+		}).
+		NoEnvar().
+		DurationVar(&params.IdleTimeout)
+
+	c.Flag("environment", `To store the offsets on the disk in environment specific paths, so that the consumer can be resumed using --from local. Defaults to the name of the active profile if one has been selected.`).
+		Short('e').
+		Default("local").
+		NoEnvar().
+		StringVar(&params.Environment)
+}
+
+// NewConsumer creates a new Kafka consumer, which stores the offsets in the local offset store.
+func NewConsumer(kafkaParams *KafkaParameters, params *ConsumerParameters, logWriter io.Writer, printer internal.Printer) (*kafka.Consumer, error) {
+	wrapper, err := kafka.NewConsumerWrapper(GetBrokers(kafkaParams.Brokers),
+		kafka.WithClusterVersion(kafkaParams.Version),
+		kafka.WithTLS(kafkaParams.TLS),
+		kafka.WithLogWriter(logWriter),
+		kafka.WithSASL(kafkaParams.SASLMechanism,
+			kafkaParams.SASLUsername,
+			kafkaParams.Password(),
+			kafkaParams.SASLHandshakeVersion,
+			kafkaParams.SASLOptions()...))
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := kafka.NewLocalOffsetStore(printer, params.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	return kafka.NewConsumer(store, wrapper, printer, false, params.Exclusive, params.IdleTimeout), nil
+}
+
+// NewCheckpoints creates the partition checkpoints based on the consumer range flags.
+func (p *ConsumerParameters) NewCheckpoints() (*kafka.PartitionCheckpoints, error) {
+	return kafka.NewPartitionCheckpoints(p.From, p.To, p.Exclusive)
+}
+
+// EventHandler handles the consumed events.
+//
+// The offset of the event will be stored if store is true. Returning an error stops the consumer.
+type EventHandler func(event *kafka.Event) (store bool, err error)
+
+// ConsumeEvents starts the consumer and hands the consumed events over to the handler,
+// until the consumer stops, ctx gets cancelled or the handler fails.
+func ConsumeEvents(ctx context.Context,
+	consumer *kafka.Consumer,
+	printer internal.Printer,
+	topics map[string]*kafka.PartitionCheckpoints,
+	handle EventHandler) error {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	defer stopConsumer()
+	var handlerErr error
+
+	go func() {
+		defer wg.Done()
+		var cancelled bool
+		for {
+			select {
+			case <-ctx.Done():
+				if !cancelled {
+					stopConsumer()
+					cancelled = true
+				}
+			case event, more := <-consumer.Events():
+				if !more {
+					consumer.CloseOffsetStore()
+					return
+				}
+				if handlerErr != nil {
+					continue
+				}
+				store, err := handle(event)
+				if err != nil {
+					handlerErr = err
+					stopConsumer()
+					continue
+				}
+				if store {
+					consumer.StoreOffset(event)
+				}
+			}
+		}
+	}()
+
+	err := consumer.Start(consumerCtx, topics)
+	if err != nil {
+		printer.Errorf(internal.Forced, "Failed to start the consumer: %s", err)
+	}
+
+	// We still need to explicitly close the underlying Kafka client, in case `consumer.Start` has not been called.
+	// It is safe to close the consumer twice.
+	consumer.Close()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return handlerErr
+}
@@ -0,0 +1,155 @@
+package copying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type copyTopic struct {
+	globalParams       *commands.GlobalParameters
+	kafkaParams        *commands.KafkaParameters
+	consumerParams     *commands.ConsumerParameters
+	destination        *destination
+	sourceTopic        string
+	destinationTopic   string
+	preservePartitions bool
+}
+
+// AddCommands adds the copy command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &copyTopic{
+		globalParams:   global,
+		kafkaParams:    kafkaParams,
+		consumerParams: &commands.ConsumerParameters{},
+	}
+	c := app.Command("copy", "Consumes the messages of a topic and publishes them to another topic, on the same or a different cluster.").Action(cmd.run)
+	c.Arg("source-topic", "The topic to copy the messages from.").
+		Required().
+		StringVar(&cmd.sourceTopic)
+	c.Arg("destination-topic", "The topic to copy the messages to. The default value is the source topic name, if the destination brokers have been specified.").
+		StringVar(&cmd.destinationTopic)
+
+	commands.BindConsumerFlags(c, cmd.consumerParams, "oldest", "0")
+	c.Flag("preserve-partitions", "Publishes each message to the same partition number on the destination topic, if the partition exists. Otherwise, the partition will be selected based on the message key. Use --no-preserve-partitions to disable.").
+		Default("true").
+		NoEnvar().
+		BoolVar(&cmd.preservePartitions)
+	cmd.destination = bindDestinationFlags(c)
+}
+
+func (c *copyTopic) run(parseContext *kingpin.ParseContext) error {
+	if internal.IsEmpty(c.sourceTopic) {
+		return errors.New("the source topic cannot be empty")
+	}
+	if err := c.destination.validate(parseContext); err != nil {
+		return err
+	}
+	if internal.IsEmpty(c.destinationTopic) {
+		if !c.destination.isSet() {
+			return errors.New("the destination topic must be specified when copying within the same cluster")
+		}
+		c.destinationTopic = c.sourceTopic
+	}
+	if !c.destination.isSet() && c.sourceTopic == c.destinationTopic {
+		return errors.New("the source and the destination topics cannot be the same on the same cluster")
+	}
+
+	checkpoints, err := c.consumerParams.NewCheckpoints()
+	if err != nil {
+		return err
+	}
+
+	destinationParams, err := c.destination.resolve(c.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	saramaLogWriter := io.Discard
+	if c.globalParams.Verbosity >= internal.Chatty {
+		saramaLogWriter = os.Stderr
+	}
+
+	prn := internal.NewPrinter(c.globalParams.Verbosity, os.Stderr)
+
+	consumer, err := commands.NewConsumer(c.kafkaParams, c.consumerParams, saramaLogWriter, prn)
+	if err != nil {
+		return err
+	}
+
+	// It is safe to close the consumer more than once.
+	defer consumer.Close()
+
+	producer, err := kafka.NewProducer(
+		commands.GetBrokers(destinationParams.Brokers),
+		kafka.WithClusterVersion(destinationParams.Version),
+		kafka.WithTLS(destinationParams.TLS),
+		kafka.WithLogWriter(saramaLogWriter),
+		kafka.WithSASL(destinationParams.SASLMechanism,
+			destinationParams.SASLUsername,
+			destinationParams.Password(),
+			destinationParams.SASLHandshakeVersion,
+			destinationParams.SASLOptions()...))
+	if err != nil {
+		return fmt.Errorf("failed to initialise the destination producer: %w", err)
+	}
+
+	defer func() {
+		err := producer.Close()
+		if err != nil {
+			fmt.Println(format.Red("Failed to close the publisher", c.globalParams.EnableColor))
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		internal.WaitForCancellationSignal()
+		prn.Info(internal.Verbose, "Stopping Trubka.")
+		cancel()
+	}()
+
+	prn.Start(map[string]io.Writer{})
+
+	counter := internal.NewCounter()
+	err = commands.ConsumeEvents(ctx, consumer, prn, map[string]*kafka.PartitionCheckpoints{c.sourceTopic: checkpoints}, func(event *kafka.Event) (bool, error) {
+		partition, offset, err := producer.ProduceEvent(c.destinationTopic, event, c.preservePartitions)
+		if err != nil {
+			counter.IncrFailure(c.destinationTopic)
+			prn.Errorf(internal.Forced,
+				"Failed to copy the message at offset %d of partition %d, topic %s: %s",
+				event.Offset,
+				event.Partition,
+				event.Topic,
+				err)
+			return false, nil
+		}
+		counter.IncrSuccess(c.destinationTopic)
+		prn.Infof(internal.VeryVerbose,
+			"The message at offset %d of partition %d has been copied to offset %d of partition %d, topic %s.",
+			event.Offset,
+			event.Partition,
+			offset,
+			partition,
+			c.destinationTopic)
+		return true, nil
+	})
+	prn.Close()
+
+	if err != nil {
+		return err
+	}
+
+	counter.PrintAsTable(c.globalParams.EnableColor)
+	return nil
+}
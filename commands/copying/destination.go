@@ -0,0 +1,61 @@
+package copying
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+)
+
+const destinationFlagPrefix = "destination-"
+
+// destination holds the parameters to connect to the destination cluster.
+type destination struct {
+	kafkaParams *commands.KafkaParameters
+	tlsParams   *commands.TLSParameters
+	// flags the destination flags which are only applicable if the destination brokers have been specified.
+	flags []*kingpin.FlagClause
+}
+
+func bindDestinationFlags(c *kingpin.CmdClause) *destination {
+	d := &destination{
+		kafkaParams: &commands.KafkaParameters{},
+	}
+	c.Flag("destination-brokers", "The comma separated list of the destination Kafka brokers in server:port format. The messages will be copied within the source cluster if not specified.").
+		StringVar(&d.kafkaParams.Brokers)
+	d.tlsParams, d.flags = commands.BindKafkaFlags(c, d.kafkaParams, destinationFlagPrefix, "Destination cluster: ")
+	return d
+}
+
+// isSet returns true if the destination cluster is different from the source cluster.
+func (d *destination) isSet() bool {
+	return !internal.IsEmpty(d.kafkaParams.Brokers)
+}
+
+// validate returns an error if any of the destination flags has been set without the destination brokers.
+func (d *destination) validate(ctx *kingpin.ParseContext) error {
+	if d.isSet() {
+		return nil
+	}
+	for _, f := range d.flags {
+		if commands.IsFlagSet(ctx, f) {
+			return fmt.Errorf("--%s can only be used along with --destination-brokers", f.Model().Name)
+		}
+	}
+	return nil
+}
+
+// resolve returns the parameters to connect to the destination cluster.
+//
+// The source cluster parameters will be returned if the destination brokers have not been specified.
+func (d *destination) resolve(source *commands.KafkaParameters) (*commands.KafkaParameters, error) {
+	if !d.isSet() {
+		return source, nil
+	}
+	if err := d.kafkaParams.Configure(d.tlsParams, destinationFlagPrefix); err != nil {
+		return nil, err
+	}
+	return d.kafkaParams, nil
+}
@@ -0,0 +1,46 @@
+package create
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type acl struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	acl          *kafka.ACL
+}
+
+func addCreateACLSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &acl{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+		acl:          &kafka.ACL{},
+	}
+	c := parent.Command("acl", "Creates a new access control list entry.").Action(cmd.run)
+	commands.BindACLFlags(c, cmd.acl, false)
+}
+
+func (c *acl) run(_ *kingpin.ParseContext) error {
+	manager, _, cancel, err := commands.InitKafkaManager(c.globalParams, c.kafkaParams)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	err = manager.CreateACL(c.acl)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The ACL for %s has been created successfully.\n", c.acl.Principal)
+	return nil
+}
@@ -11,4 +11,5 @@ func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, ka
 	parent := app.Command("create", "A command to create Kafka entities.")
 	addCreateTopicSubCommand(parent, global, kafkaParams)
 	addCreatePartitionsSubCommand(parent, global, kafkaParams)
+	addCreateACLSubCommand(parent, global, kafkaParams)
 }
@@ -0,0 +1,76 @@
+package deletion
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type acl struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	filter       *kafka.ACL
+	silent       bool
+	format       string
+	style        string
+}
+
+func addDeleteACLSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &acl{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+		filter:       &kafka.ACL{},
+	}
+	c := parent.Command("acl", "Deletes the access control list entries matching the filters.").Action(cmd.run)
+	commands.BindACLFlags(c, cmd.filter, true)
+	c.Flag("silent", "Deletes the ACLs without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (c *acl) run(_ *kingpin.ParseContext) error {
+	if c.filter.MatchesAll() {
+		return errors.New("at least one filter which does not match everything must be provided to delete the ACLs")
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(c.globalParams, c.kafkaParams)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	matches, err := manager.ListACLs(ctx, c.filter)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		return internal.NotFoundError("ACL", "", nil)
+	}
+
+	err = commands.PrintACLs(matches, c.format, c.style, c.globalParams.EnableColor)
+	if err != nil {
+		return err
+	}
+
+	if c.silent || commands.AskForConfirmation(fmt.Sprintf("Are you sure you want to delete %d ACL(s)", len(matches))) {
+		deleted, err := manager.DeleteACLs(c.filter)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d ACL(s) have been deleted successfully.\n", len(deleted))
+	}
+	return nil
+}
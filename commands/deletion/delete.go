@@ -11,5 +11,7 @@ func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, ka
 	parent := app.Command("delete", "A command to delete Kafka entities.")
 	addDeleteTopicSubCommand(parent, global, kafkaParams)
 	addDeleteGroupSubCommand(parent, global, kafkaParams)
+	addDeleteACLSubCommand(parent, global, kafkaParams)
+	addDeleteRecordsSubCommand(parent, global, kafkaParams)
 	addDeleteLocalOffsetsSubCommand(parent, global)
 }
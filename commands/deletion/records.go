@@ -0,0 +1,144 @@
+package deletion
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type records struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topic        string
+	before       []string
+	silent       bool
+	format       string
+	style        string
+}
+
+func addDeleteRecordsSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &records{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("records", "Deletes the records from the beginning of the topic partitions, up to (not including) the specified offsets.").Action(cmd.run)
+	c.Arg("topic", "The topic to delete the records from.").
+		Required().
+		StringVar(&cmd.topic)
+	c.Flag("before", "The offset or the timestamp before which the records will be deleted, using the same syntax as the --from flag of the consume commands (eg. 100, 1#100, 2020-07-15T14:24:23+10:00 or latest). Repeat the flag to target multiple partitions.").
+		PlaceHolder("[PARTITION#]OFFSET|TIMESTAMP").
+		Required().
+		NoEnvar().
+		StringsVar(&cmd.before)
+	c.Flag("silent", "Deletes the records without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (r *records) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(r.topic) {
+		return errors.New("the topic name cannot be empty")
+	}
+
+	checkpoints, err := kafka.NewTargetCheckpoints(r.before)
+	if err != nil {
+		return err
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(r.globalParams, r.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	deletions, err := manager.GetRecordsDeletion(ctx, r.topic, checkpoints)
+	if err != nil {
+		return err
+	}
+
+	if len(deletions) == 0 {
+		fmt.Println("There are no records to delete.")
+		return nil
+	}
+
+	switch r.format {
+	case commands.JSONFormat:
+		err = output.PrintAsJSON(deletions, r.style, r.globalParams.EnableColor)
+	case commands.TableFormat:
+		r.printAsTable(deletions)
+	case commands.TreeFormat:
+		r.printAsList(deletions, false)
+	case commands.PlainTextFormat:
+		r.printAsList(deletions, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	if r.silent || commands.AskForConfirmation(fmt.Sprintf("Are you sure you want to delete the records of %s topic", r.topic)) {
+		err := manager.DeleteRecords(r.topic, deletions)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The records of %s topic have been deleted successfully.\n", r.topic)
+	}
+	return nil
+}
+
+func (r *records) printAsTable(deletions []kafka.RecordsDeletion) {
+	table := tabular.NewTable(r.globalParams.EnableColor,
+		tabular.C("Partition").MinWidth(10),
+		tabular.C("Earliest").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Latest").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Delete Before").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Records").MinWidth(10).Humanize().FAlign(tabular.AlignCenter),
+	)
+	table.SetTitle(fmt.Sprintf("Topic: %s", r.topic))
+	var total int64
+	for _, deletion := range deletions {
+		count := deletion.Count()
+		total += count
+		table.AddRow(
+			strconv.FormatInt(int64(deletion.Partition), 10),
+			humanize.Comma(deletion.Earliest),
+			humanize.Comma(deletion.Latest),
+			humanize.Comma(deletion.Before),
+			count,
+		)
+	}
+	table.AddFooter(" ", " ", " ", " ", total)
+	table.Render()
+}
+
+func (r *records) printAsList(deletions []kafka.RecordsDeletion, plain bool) {
+	l := list.New(plain)
+	l.AddItem(r.topic)
+	l.Indent()
+	for _, deletion := range deletions {
+		l.AddItemF("P%d", deletion.Partition)
+		l.Indent()
+		l.AddItemF("     Earliest: %s", humanize.Comma(deletion.Earliest))
+		l.AddItemF("       Latest: %s", humanize.Comma(deletion.Latest))
+		l.AddItemF("Delete Before: %s", humanize.Comma(deletion.Before))
+		l.AddItemF("      Records: %s", humanize.Comma(deletion.Count()))
+		l.UnIndent()
+	}
+	l.UnIndent()
+	l.Render()
+}
@@ -95,7 +95,14 @@ func (t *topic) printAsList(meta *kafka.TopicMetadata, plain bool) error {
 			l.AddItemF("Offset: %s", humanize.Comma(pm.Offset))
 			totalOffsets += pm.Offset
 		}
-		l.AddItemF("Leader: %s", pm.Leader.String())
+		switch {
+		case !pm.HasLeader():
+			l.AddItem("Leader: none")
+		case pm.NonPreferredLeader():
+			l.AddItemF("Leader: %s (not preferred)", pm.Leader.String())
+		default:
+			l.AddItemF("Leader: %s", pm.Leader.String())
+		}
 		l.AddItemF("ISRs: %s", t.brokersToLine(pm.ISRs...))
 		l.AddItemF("Replicas: %s", t.brokersToLine(pm.Replicas...))
 		if len(pm.OfflineReplicas) > 0 {
@@ -123,7 +130,16 @@ func (t *topic) printAsTable(meta *kafka.TopicMetadata) error {
 	)
 	table.SetTitle(format.WithCount("Partitions", len(meta.Partitions)))
 	var totalOffsets int64
+	var nonPreferred int
 	for _, pm := range meta.Partitions {
+		leader := pm.Leader.MarkedHostName()
+		switch {
+		case !pm.HasLeader():
+			leader = "none"
+		case pm.NonPreferredLeader():
+			leader += kafka.NonPreferredLeaderLabel
+			nonPreferred++
+		}
 		offset := "-"
 		if t.includeOffsets {
 			offset = humanize.Comma(pm.Offset)
@@ -132,7 +148,9 @@ func (t *topic) printAsTable(meta *kafka.TopicMetadata) error {
 		table.AddRow(
 			pm.ID,
 			offset,
-			format.SpaceIfEmpty(pm.Leader.MarkedHostName()),
+			format.RedIfTrue(format.SpaceIfEmpty(leader), func() bool {
+				return !pm.HasLeader() || pm.NonPreferredLeader()
+			}, t.globalParams.EnableColor),
 			format.SpaceIfEmpty(t.brokersToList(pm.Replicas...)),
 			format.SpaceIfEmpty(t.brokersToList(pm.OfflineReplicas...)),
 			format.SpaceIfEmpty(t.brokersToList(pm.ISRs...)),
@@ -144,7 +162,11 @@ func (t *topic) printAsTable(meta *kafka.TopicMetadata) error {
 		total = humanize.Comma(totalOffsets)
 	}
 	table.AddFooter(fmt.Sprintf("Total: %d", len(meta.Partitions)), total, " ", " ", " ", " ")
-	table.SetCaption(kafka.ControllerBrokerLabel + " CONTROLLER NODES")
+	caption := kafka.ControllerBrokerLabel + " CONTROLLER NODES"
+	if nonPreferred > 0 {
+		caption += fmt.Sprintf("   %s NON-PREFERRED LEADERS [%d]", kafka.NonPreferredLeaderLabel, nonPreferred)
+	}
+	table.SetCaption(caption)
 	table.Render()
 
 	if t.loadConfigs {
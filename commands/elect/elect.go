@@ -0,0 +1,13 @@
+package elect
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+)
+
+// AddCommands adds the elect command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	parent := app.Command("elect", "A command to elect Kafka partition leaders.")
+	addLeadersSubCommand(parent, global, kafkaParams)
+}
@@ -0,0 +1,141 @@
+package elect
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type leaders struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topic        string
+	partitions   []int32
+	electionType string
+	silent       bool
+	format       string
+	style        string
+}
+
+func addLeadersSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &leaders{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("leaders", "Elects the leaders of the topic partitions.").Action(cmd.run)
+	c.Arg("topic", "The topic to elect the partition leaders of. The leaders of all the topics will be elected if not specified.").
+		StringVar(&cmd.topic)
+	c.Flag("partition", "The partition to elect the leader of. Repeat the flag to elect the leaders of multiple partitions. All the partitions will be included if not specified.").
+		Short('p').
+		NoEnvar().
+		Int32ListVar(&cmd.partitions)
+	c.Flag("type", "The election type. The preferred election restores the leadership to the first replica of each partition. The unclean election elects an out of sync replica if no in-sync replicas are available, which may cause data loss.").
+		Default(kafka.PreferredElection).
+		NoEnvar().
+		EnumVar(&cmd.electionType, kafka.PreferredElection, kafka.UncleanElection)
+	c.Flag("silent", "Performs the unclean election without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (l *leaders) run(_ *kingpin.ParseContext) error {
+	if l.electionType == kafka.UncleanElection && !l.silent {
+		target := "all the topics"
+		if !internal.IsEmpty(l.topic) {
+			target = l.topic + " topic"
+		}
+		if !commands.AskForConfirmation(fmt.Sprintf("Unclean leader election may cause data loss. Are you sure you want to elect the leaders of %s", target)) {
+			return nil
+		}
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(l.globalParams, l.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	elections, err := manager.ElectLeaders(ctx, l.electionType, l.topic, l.partitions)
+	if err != nil {
+		return err
+	}
+
+	if len(elections) == 0 {
+		fmt.Println("No partitions have been found.")
+		return nil
+	}
+
+	switch l.format {
+	case commands.JSONFormat:
+		return output.PrintAsJSON(elections, l.style, l.globalParams.EnableColor)
+	case commands.TableFormat:
+		l.printAsTable(elections)
+	case commands.TreeFormat:
+		l.printAsList(elections, false)
+	case commands.PlainTextFormat:
+		l.printAsList(elections, true)
+	}
+	return nil
+}
+
+func (l *leaders) printAsTable(elections []*kafka.LeaderElection) {
+	table := tabular.NewTable(l.globalParams.EnableColor,
+		tabular.C("Topic").Align(tabular.AlignLeft),
+		tabular.C("Partition").MinWidth(10),
+		tabular.C("Status").MinWidth(10),
+		tabular.C("Error").Align(tabular.AlignLeft).MaxWidth(100),
+	)
+	table.SetTitle(format.WithCount("Leader Elections", len(elections)))
+	var elected int
+	for _, election := range elections {
+		if election.Elected {
+			elected++
+		}
+		table.AddRow(
+			election.Topic,
+			strconv.FormatInt(int64(election.Partition), 10),
+			format.RedIfTrue(election.Status(), func() bool {
+				return election.Error != ""
+			}, l.globalParams.EnableColor),
+			format.SpaceIfEmpty(election.Error),
+		)
+	}
+	table.AddFooter(" ", " ", fmt.Sprintf("Elected: %d", elected), " ")
+	table.Render()
+}
+
+func (l *leaders) printAsList(elections []*kafka.LeaderElection, plain bool) {
+	ls := list.New(plain)
+	var topic string
+	for _, election := range elections {
+		if election.Topic != topic {
+			if topic != "" {
+				ls.UnIndent()
+			}
+			topic = election.Topic
+			ls.AddItem(topic)
+			ls.Indent()
+		}
+		if election.Error != "" {
+			ls.AddItemF("P%d: %s (%s)", election.Partition, election.Status(), election.Error)
+			continue
+		}
+		ls.AddItemF("P%d: %s", election.Partition, election.Status())
+	}
+	ls.Render()
+}
@@ -1,6 +1,8 @@
 package commands
 
-import "github.com/xitonix/trubka/internal"
+import (
+	"github.com/xitonix/trubka/internal"
+)
 
 // GlobalParameters holds the app's global parameters available to all the sub-commands.
 type GlobalParameters struct {
@@ -8,4 +10,10 @@ type GlobalParameters struct {
 	Verbosity internal.VerbosityLevel
 	// EnableColor enables colours across all the sub-commands.
 	EnableColor bool
+	// ConfigFile the path to the configuration file.
+	ConfigFile string
+	// ProfileName the name of the cluster profile selected by the user.
+	ProfileName string
+	// ErrorExitCode the exit code of the failures which do not have a specific exit code. Defaults to 1 if not set.
+	ErrorExitCode int
 }
@@ -2,6 +2,16 @@ package commands
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
 )
 
 // KafkaParameters holds CLI parameters to connect to Kafka.
@@ -18,8 +28,217 @@ type KafkaParameters struct {
 	SASLUsername string
 	// SASLPassword SASL password.
 	SASLPassword string
+	// SASLPasswordFile the path to the file to read the SASL password from.
+	SASLPasswordFile string
+	// SASLPasswordCommand the command to run to read the SASL password from its standard output.
+	SASLPasswordCommand string
 	// SASLHandshakeVersion SASL handshake version.
 	SASLHandshakeVersion string
+	// OAuthToken the static OAuth bearer token.
+	OAuthToken string
+	// OAuthTokenFile the path to the file to read the OAuth bearer token from.
+	OAuthTokenFile string
+	// OAuthTokenURL the OAuth token endpoint to request the tokens from, using the client credentials grant.
+	OAuthTokenURL string
+	// OAuthScopes a comma separated list of the scopes to request from the token endpoint.
+	OAuthScopes string
+	// Kerberos the GSSAPI (Kerberos) authentication settings.
+	Kerberos kafka.KerberosSettings
+	password kafka.SASLPassword
+}
+
+// FlagBinder defines new flags. It is implemented by both kingpin.Application and kingpin.CmdClause.
+type FlagBinder interface {
+	Flag(name, help string) *kingpin.FlagClause
+}
+
+// BindKafkaFlags binds the flags to connect to a Kafka cluster, except the brokers, to the app or command.
+//
+// The prefix is prepended to the name of the flags and the help prefix to the help texts, so that the flags of
+// more than one cluster can be bound to the same command. The TLS parameters and the bound flags are returned.
+func BindKafkaFlags(binder FlagBinder, params *KafkaParameters, prefix, helpPrefix string) (*TLSParameters, []*kingpin.FlagClause) {
+	var flags []*kingpin.FlagClause
+	flag := func(name, help string) *kingpin.FlagClause {
+		f := binder.Flag(prefix+name, helpPrefix+strings.ReplaceAll(help, "--", "--"+prefix))
+		flags = append(flags, f)
+		return f
+	}
+
+	flag("kafka-version", "Kafka cluster version.").
+		Default(kafka.DefaultClusterVersion).
+		StringVar(&params.Version)
+
+	bindSASLFlags(flag, params)
+	tlsParams := bindTLSFlags(flag)
+	return tlsParams, flags
+}
+
+func bindSASLFlags(flag func(name, help string) *kingpin.FlagClause, params *KafkaParameters) {
+	flag("sasl-mechanism", "SASL authentication mechanism.").
+		Default(kafka.SASLMechanismNone).
+		EnumVar(&params.SASLMechanism,
+			kafka.SASLMechanismNone,
+			kafka.SASLMechanismPlain,
+			kafka.SASLMechanismSCRAM256,
+			kafka.SASLMechanismSCRAM512,
+			kafka.SASLMechanismOAuth,
+			kafka.SASLMechanismGSSAPI)
+	flag("sasl-username", "SASL authentication username. The client ID for the OAuth token endpoint and the Kerberos principal for gssapi. Will be ignored if --sasl-mechanism is set to none.").
+		StringVar(&params.SASLUsername)
+	flag("sasl-password", "SASL authentication password. Will be ignored if --sasl-mechanism is set to none. The password will be prompted for if neither --sasl-password, --sasl-password-file nor --sasl-password-cmd has been set.").
+		StringVar(&params.SASLPassword)
+	flag("sasl-password-file", "The file to read the SASL authentication password from. Will be ignored if --sasl-mechanism is set to none.").
+		PlaceHolder("FILE").
+		ExistingFileVar(&params.SASLPasswordFile)
+	flag("sasl-password-cmd", "The command to run to read the SASL authentication password from its standard output (eg. 'pass show kafka/prod'). Will be ignored if --sasl-mechanism is set to none.").
+		PlaceHolder("COMMAND").
+		StringVar(&params.SASLPasswordCommand)
+	flag("sasl-version", "SASL handshake version. Will be ignored if --sasl-mechanism is set to none.").
+		Default(string(kafka.SASLHandshakeV1)).
+		EnumVar(&params.SASLHandshakeVersion, string(kafka.SASLHandshakeV0), string(kafka.SASLHandshakeV1))
+	flag("sasl-oauth-token", "The static OAuth bearer token. Applicable to --sasl-mechanism=oauthbearer only.").
+		PlaceHolder("TOKEN").
+		StringVar(&params.OAuthToken)
+	flag("sasl-oauth-token-file", "The file to read the OAuth bearer token from. The file will be re-read for every new connection. Applicable to --sasl-mechanism=oauthbearer only.").
+		PlaceHolder("FILE").
+		ExistingFileVar(&params.OAuthTokenFile)
+	flag("sasl-oauth-token-url", "The OAuth token endpoint to request the bearer tokens from, using the client credentials grant. The SASL username and password will be used as the client ID and secret. Applicable to --sasl-mechanism=oauthbearer only.").
+		PlaceHolder("URL").
+		StringVar(&params.OAuthTokenURL)
+	flag("sasl-oauth-scopes", "A comma separated list of the scopes to request from the OAuth token endpoint.").
+		PlaceHolder("SCOPES").
+		StringVar(&params.OAuthScopes)
+	flag("sasl-kerberos-config", "The path to the Kerberos configuration file. Applicable to --sasl-mechanism=gssapi only.").
+		Default("/etc/krb5.conf").
+		StringVar(&params.Kerberos.ConfigPath)
+	flag("sasl-kerberos-keytab", "The keytab file to authenticate the Kerberos principal with. The SASL password will be used if not set. Applicable to --sasl-mechanism=gssapi only.").
+		PlaceHolder("FILE").
+		ExistingFileVar(&params.Kerberos.KeyTabPath)
+	flag("sasl-kerberos-service-name", "The Kerberos service name of the brokers. Applicable to --sasl-mechanism=gssapi only.").
+		Default(kafka.DefaultKerberosServiceName).
+		StringVar(&params.Kerberos.ServiceName)
+	flag("sasl-kerberos-realm", "The Kerberos realm. Applicable to --sasl-mechanism=gssapi only.").
+		StringVar(&params.Kerberos.Realm)
+	flag("sasl-kerberos-disable-fast", "Disables the Kerberos FAST negotiation (PA-FX-FAST), which is not supported by some KDCs.").
+		BoolVar(&params.Kerberos.DisablePAFXFAST)
+}
+
+func bindTLSFlags(flag func(name, help string) *kingpin.FlagClause) *TLSParameters {
+	t := &TLSParameters{}
+	flag("tls", "Enables TLS (Unverified by default). Mutual authentication can also be enabled by providing client key and certificate.").
+		BoolVar(&t.Enabled)
+	flag("ca-cert", `Trusted root certificates for verifying the server. If neither --ca-cert nor --tls-verify-system-roots is set, Trubka will skip server certificate and domain verification.`).
+		ExistingFileVar(&t.CACert)
+	flag("client-cert", `Client certification file to enable mutual TLS authentication. Client key must also be provided.`).
+		ExistingFileVar(&t.ClientCert)
+	flag("client-key", `Client private key file to enable mutual TLS authentication. Client certificate must also be provided.`).
+		ExistingFileVar(&t.ClientKey)
+	flag("tls-key-password", "The password to decrypt the client private key or the PKCS#12 bundle.").
+		PlaceHolder("PASSWORD").
+		StringVar(&t.KeyPassword)
+	flag("tls-pkcs12", "The PKCS#12 bundle (.p12 or .pfx) containing the client certificate and private key to enable mutual TLS authentication. Cannot be used along with --client-cert.").
+		PlaceHolder("FILE").
+		ExistingFileVar(&t.PKCS12)
+	flag("tls-verify-system-roots", "Verifies the server certificates using the system root certificates, in addition to --ca-cert.").
+		BoolVar(&t.VerifySystemRoots)
+	flag("tls-server-name", "The expected host name of the server certificates. The broker addresses will be used by default.").
+		StringVar(&t.ServerName)
+	flag("tls-min-version", "The minimum accepted TLS version.").
+		EnumVar(&t.MinVersion, "1.0", "1.1", "1.2", "1.3")
+	flag("tls-cipher-suites", "A comma separated list of the TLS 1.2 (and earlier) cipher suites to enable (eg. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256). The TLS 1.3 cipher suites are not configurable.").
+		PlaceHolder("SUITES").
+		StringVar(&t.CipherSuites)
+	return t
+}
+
+// Configure validates the SASL settings and initialises the TLS configuration if TLS has been enabled.
+//
+// The flag prefix is used to refer to the flags in the error messages.
+func (k *KafkaParameters) Configure(tlsParams *TLSParameters, flagPrefix string) error {
+	if err := k.ValidateSASLPassword(flagPrefix); err != nil {
+		return err
+	}
+	if err := k.ValidateOAuthToken(flagPrefix); err != nil {
+		return err
+	}
+	if !tlsParams.Enabled {
+		return nil
+	}
+	tlsConfig, err := ConfigureTLS(tlsParams)
+	if err != nil {
+		return err
+	}
+	k.TLS = tlsConfig
+	return nil
+}
+
+// SASLOptions returns the mechanism specific SASL authentication options.
+func (k *KafkaParameters) SASLOptions() []kafka.SASLOption {
+	options := []kafka.SASLOption{kafka.WithKerberos(k.Kerberos)}
+	switch {
+	case !internal.IsEmpty(k.OAuthToken):
+		options = append(options, kafka.WithOAuthToken(k.OAuthToken))
+	case !internal.IsEmpty(k.OAuthTokenFile):
+		options = append(options, kafka.WithOAuthTokenFile(k.OAuthTokenFile))
+	case !internal.IsEmpty(k.OAuthTokenURL):
+		var scopes []string
+		for _, scope := range strings.Split(k.OAuthScopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		options = append(options, kafka.WithOAuthTokenEndpoint(k.OAuthTokenURL, scopes...))
+	}
+	return options
+}
+
+// ValidateSASLPassword makes sure that the SASL password has not been provided through more than one source.
+func (k *KafkaParameters) ValidateSASLPassword(flagPrefix string) error {
+	var set int
+	for _, source := range []string{k.SASLPassword, k.SASLPasswordFile, k.SASLPasswordCommand} {
+		if !internal.IsEmpty(source) {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("only one of --%[1]ssasl-password, --%[1]ssasl-password-file and --%[1]ssasl-password-cmd can be set", flagPrefix)
+	}
+	return nil
+}
+
+// ValidateOAuthToken makes sure that the OAuth bearer token has not been provided through more than one source.
+func (k *KafkaParameters) ValidateOAuthToken(flagPrefix string) error {
+	var set int
+	for _, source := range []string{k.OAuthToken, k.OAuthTokenFile, k.OAuthTokenURL} {
+		if !internal.IsEmpty(source) {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("only one of --%[1]ssasl-oauth-token, --%[1]ssasl-oauth-token-file and --%[1]ssasl-oauth-token-url can be set", flagPrefix)
+	}
+	return nil
+}
+
+// Password returns the source of the SASL password.
+//
+// The user will be prompted to enter the password if neither the password, nor the password file or the password
+// command has been set. The same source is returned on subsequent calls, so that the password is only read once.
+func (k *KafkaParameters) Password() kafka.SASLPassword {
+	if k.password != nil {
+		return k.password
+	}
+	switch {
+	case !internal.IsEmpty(k.SASLPassword):
+		k.password = kafka.StaticPassword(k.SASLPassword)
+	case !internal.IsEmpty(k.SASLPasswordFile):
+		k.password = kafka.PasswordFile(k.SASLPasswordFile)
+	case !internal.IsEmpty(k.SASLPasswordCommand):
+		k.password = kafka.PasswordCommand(k.SASLPasswordCommand)
+	default:
+		k.password = kafka.PasswordPrompt(k.SASLUsername)
+	}
+	return k.password
 }
 
 // TLSParameters holds TLS connection parameters.
@@ -28,10 +247,100 @@ type TLSParameters struct {
 	Enabled bool
 	// CACert the path to CA Cert file.
 	CACert string
+	// VerifySystemRoots verifies the server certificates using the system root certificates, in addition to CACert.
+	VerifySystemRoots bool
+	// ServerName the expected host name of the server certificates.
+	//
+	// The host name of the brokers will be used if not set.
+	ServerName string
+	// MinVersion the minimum accepted TLS version (eg. 1.2).
+	MinVersion string
+	// CipherSuites a comma separated list of the enabled TLS 1.2 (and earlier) cipher suites.
+	CipherSuites string
 	// ClientCert path to client certification file to enable mutual TLS authentication.
 	ClientCert string
 	// ClientCert path to client private key file to enable mutual TLS authentication.
 	//
 	// If set, the client certificate file must also be provided.
 	ClientKey string
+	// PKCS12 the path to the PKCS#12 bundle, containing the client certificate and private key.
+	PKCS12 string
+	// KeyPassword the password to decrypt the client private key or the PKCS#12 bundle.
+	KeyPassword string
+}
+
+// ConfigureTLS creates a new TLS configuration based on the TLS parameters.
+func ConfigureTLS(params *TLSParameters) (*tls.Config, error) {
+	tlsConf := tls.Config{
+		ServerName: params.ServerName,
+	}
+
+	if !internal.IsEmpty(params.MinVersion) {
+		version, err := internal.ParseTLSVersion(params.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.MinVersion = version
+	}
+
+	if !internal.IsEmpty(params.CipherSuites) {
+		suites, err := internal.ParseCipherSuites(params.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.CipherSuites = suites
+	}
+
+	// Mutual authentication is enabled if either the PKCS#12 bundle or the client certificate is provided.
+	switch {
+	case !internal.IsEmpty(params.PKCS12):
+		if !internal.IsEmpty(params.ClientCert) || !internal.IsEmpty(params.ClientKey) {
+			return nil, errors.New("the client certificate and key cannot be used along with the PKCS#12 bundle")
+		}
+		certificate, err := internal.LoadPKCS12(params.PKCS12, params.KeyPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the PKCS#12 bundle: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{certificate}
+	case !internal.IsEmpty(params.ClientCert):
+		// Both client key and certificate are needed.
+		if internal.IsEmpty(params.ClientKey) {
+			return nil, errors.New("TLS client key is missing. Mutual authentication cannot be used")
+		}
+		certificate, err := internal.LoadX509KeyPair(params.ClientCert, params.ClientKey, params.KeyPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client TLS key pair: %w", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{certificate}
+	}
+
+	if internal.IsEmpty(params.CACert) && !params.VerifySystemRoots {
+		// Server cert verification will be disabled.
+		// Only standard trusted certificates are used to verify the server certs.
+		tlsConf.InsecureSkipVerify = true
+		return &tlsConf, nil
+	}
+
+	certPool := x509.NewCertPool()
+	if params.VerifySystemRoots {
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the system root certificates: %w", err)
+		}
+		certPool = systemPool
+	}
+
+	if !internal.IsEmpty(params.CACert) {
+		ca, err := os.ReadFile(params.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate: %w", err)
+		}
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return nil, errors.New("failed to append the CA certificate to the pool")
+		}
+	}
+
+	tlsConf.RootCAs = certPool
+
+	return &tlsConf, nil
 }
@@ -0,0 +1,52 @@
+package list
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type acls struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	filter       *kafka.ACL
+	format       string
+	style        string
+}
+
+func addACLsSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &acls{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+		filter:       &kafka.ACL{},
+	}
+	c := parent.Command("acls", "Lists the access control list entries.").Action(cmd.run)
+	commands.BindACLFlags(c, cmd.filter, true)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (a *acls) run(_ *kingpin.ParseContext) error {
+	manager, ctx, cancel, err := commands.InitKafkaManager(a.globalParams, a.kafkaParams)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	entries, err := manager.ListACLs(ctx, a.filter)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return internal.NotFoundError("ACL", "", nil)
+	}
+
+	return commands.PrintACLs(entries, a.format, a.style, a.globalParams.EnableColor)
+}
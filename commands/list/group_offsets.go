@@ -1,11 +1,17 @@
 package list
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
+	"golang.org/x/term"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/xitonix/trubka/commands"
@@ -22,6 +28,7 @@ type groupOffset struct {
 	globalParams *commands.GlobalParameters
 	group        string
 	topicFilter  *regexp.Regexp
+	watch        time.Duration
 	format       string
 	style        string
 }
@@ -36,10 +43,21 @@ func addGroupOffsetsSubCommand(parent *kingpin.CmdClause, global *commands.Globa
 	c.Flag("topic-filter", "An optional regular expression to filter the topics by.").
 		Short('t').
 		RegexpVar(&cmd.topicFilter)
+	c.Flag("watch", fmt.Sprintf("Refreshes the offsets at the specified interval (eg. 5s), along with the lag changes and the consume rate of each partition since the last refresh. Applicable to --format=%s only.", commands.TableFormat)).
+		Short('w').
+		NoEnvar().
+		DurationVar(&cmd.watch)
 	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
 }
 
 func (g *groupOffset) run(_ *kingpin.ParseContext) error {
+	if g.watch < 0 {
+		return errors.New("the watch interval cannot be negative")
+	}
+	if g.watch > 0 && g.format != commands.TableFormat {
+		return fmt.Errorf("the watch mode is only available in %s format", commands.TableFormat)
+	}
+
 	manager, ctx, cancel, err := commands.InitKafkaManager(g.globalParams, g.kafkaParams)
 
 	if err != nil {
@@ -51,6 +69,10 @@ func (g *groupOffset) run(_ *kingpin.ParseContext) error {
 		cancel()
 	}()
 
+	if g.watch > 0 {
+		return g.watchOffsets(ctx, manager)
+	}
+
 	topics, err := manager.GetGroupOffsets(ctx, g.group, g.topicFilter)
 	if err != nil {
 		return err
@@ -103,6 +125,100 @@ func (g *groupOffset) printAsTable(topics kafka.TopicPartitionOffset) error {
 	return nil
 }
 
+func (g *groupOffset) watchOffsets(ctx context.Context, manager *kafka.Manager) error {
+	var (
+		previous  kafka.TopicPartitionOffset
+		refreshed time.Time
+	)
+	// The escape sequences would end up as garbage in the files and pipes.
+	redraw := term.IsTerminal(int(os.Stdout.Fd()))
+	for {
+		topics, err := manager.GetGroupOffsets(ctx, g.group, g.topicFilter)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		now := time.Now()
+		if len(topics) == 0 {
+			return internal.NotFoundError("topic", "topic", g.topicFilter)
+		}
+
+		if redraw {
+			// Moves the cursor to the top left corner and clears the screen, to redraw the tables in place.
+			fmt.Print("\033[H\033[2J")
+		} else if !refreshed.IsZero() {
+			fmt.Println(strings.Repeat("─", 80))
+		}
+		fmt.Printf("Group: %s, Refreshed at: %s (every %s)\n", g.group, now.Format(time.RFC3339), g.watch)
+		for _, topic := range topics.SortedTopics() {
+			g.printProgressTable(topic, topics[topic], topics[topic].Progress(previous[topic], now.Sub(refreshed)))
+		}
+		previous, refreshed = topics, now
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(g.watch):
+		}
+	}
+}
+
+func (g *groupOffset) printProgressTable(topic string, partitionOffsets kafka.PartitionOffset, progress map[int32]kafka.OffsetProgress) {
+	table := tabular.NewTable(g.globalParams.EnableColor,
+		tabular.C("Partition").MinWidth(10),
+		tabular.C("Latest").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Current").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Lag").MinWidth(10).Humanize().FAlign(tabular.AlignCenter).Warn(0, true),
+		tabular.C("Lag Delta").MinWidth(10).Align(tabular.AlignCenter),
+		tabular.C("Rate (msg/s)").MinWidth(10).Align(tabular.AlignCenter),
+	)
+	table.SetTitle(fmt.Sprintf("Topic: %s", topic))
+	var (
+		totalLag   int64
+		totalDelta int64
+		totalRate  float64
+	)
+	for _, partition := range partitionOffsets.SortPartitions() {
+		offsets := partitionOffsets[int32(partition)]
+		lag := offsets.Lag()
+		totalLag += lag
+		delta, rate := "-", "-"
+		if p, ok := progress[int32(partition)]; ok {
+			totalDelta += p.LagDelta
+			totalRate += p.Rate
+			delta = g.formatLagDelta(p.LagDelta)
+			rate = humanize.CommafWithDigits(p.Rate, 1)
+		}
+		table.AddRow(
+			strconv.FormatInt(int64(partition), 10),
+			humanize.Comma(offsets.Latest),
+			humanize.Comma(offsets.Current),
+			lag,
+			delta,
+			rate,
+		)
+	}
+	if len(progress) == 0 {
+		table.AddFooter(" ", " ", " ", totalLag, " ", " ")
+	} else {
+		table.AddFooter(" ", " ", " ", totalLag, g.formatLagDelta(totalDelta), humanize.CommafWithDigits(totalRate, 1))
+	}
+	table.Render()
+}
+
+func (g *groupOffset) formatLagDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprint(format.Red("+"+humanize.Comma(delta), g.globalParams.EnableColor))
+	case delta < 0:
+		return fmt.Sprint(format.Green(humanize.Comma(delta), g.globalParams.EnableColor))
+	default:
+		return "0"
+	}
+}
+
 func (g *groupOffset) printAsList(topics kafka.TopicPartitionOffset, plain bool) error {
 	l := list.New(plain)
 	if !plain {
@@ -12,6 +12,7 @@ func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, ka
 	addTopicsSubCommand(parent, global, kafkaParams)
 	addGroupsSubCommand(parent, global, kafkaParams)
 	addGroupOffsetsSubCommand(parent, global, kafkaParams)
+	addACLsSubCommand(parent, global, kafkaParams)
 	addLocalOffsetsSubCommand(parent, global, kafkaParams)
 	addLocalTopicsSubCommand(parent, global)
 }
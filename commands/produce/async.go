@@ -0,0 +1,102 @@
+package produce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/kafka"
+)
+
+func produceAsync(ctx context.Context,
+	kafkaParams *commands.KafkaParameters,
+	globalParams *commands.GlobalParameters,
+	topic string,
+	params *producerParams,
+	next messageSource,
+	serialize valueSerializer) error {
+	brokers := commands.GetBrokers(kafkaParams.Brokers)
+	producer, err := kafka.NewAsyncProducer(brokers, producerOptions(kafkaParams, globalParams.Verbosity, &params.settings)...)
+	if err != nil {
+		return err
+	}
+
+	summary := newDeliverySummary(params.rate)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for report := range producer.Reports() {
+			summary.add(report)
+			printDeliveryReport(report, globalParams)
+		}
+	}()
+
+	start := time.Now()
+	err = enqueue(ctx, producer, globalParams, topic, params, next, serialize)
+	if globalParams.Verbosity >= internal.VeryVerbose {
+		fmt.Println("Waiting for the in-flight messages to be acknowledged.")
+	}
+	producer.Close()
+	<-done
+	summary.elapsed = time.Since(start)
+	summary.print(globalParams.EnableColor)
+	return err
+}
+
+func enqueue(ctx context.Context,
+	producer *kafka.AsyncProducer,
+	globalParams *commands.GlobalParameters,
+	topic string,
+	params *producerParams,
+	next messageSource,
+	serialize valueSerializer) error {
+	throttle := newThrottler(params, globalParams.Verbosity)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			msg, err := next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if throttle.wait(ctx) != nil {
+				return nil
+			}
+			vBytes, err := serialize(msg.value)
+			if err != nil {
+				return err
+			}
+			err = producer.Produce(ctx, topic, []byte(msg.key), vBytes, msg.headers, msg.partition)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to publish to kafka: %w", err)
+			}
+		}
+	}
+}
+
+func printDeliveryReport(report *kafka.DeliveryReport, globalParams *commands.GlobalParameters) {
+	if report.Err != nil {
+		if globalParams.Verbosity >= internal.Verbose {
+			fmt.Println(format.Red(fmt.Sprintf("Failed to publish the message: %s", report.Err), globalParams.EnableColor))
+		}
+		return
+	}
+	if globalParams.Verbosity >= internal.VeryVerbose {
+		fmt.Printf("Message has been published to the offset %d of partition %d in %s.\n",
+			report.Offset,
+			report.Partition,
+			report.Latency)
+	}
+}
@@ -0,0 +1,84 @@
+package produce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	jsonLinesInput = "json-lines"
+	jsonArrayInput = "json-array"
+)
+
+// inputRecord represents a message in the input file.
+//
+// The value can either be a Json string, or any other Json value which will be published as is.
+type inputRecord struct {
+	Key       *string           `json:"key"`
+	Value     json.RawMessage   `json:"value"`
+	Headers   map[string]string `json:"headers"`
+	Partition *int32            `json:"partition"`
+}
+
+func (r *inputRecord) content() (string, error) {
+	raw := strings.TrimSpace(string(r.Value))
+	if raw == "" || raw == "null" {
+		return "", errors.New("the value cannot be empty")
+	}
+	if strings.HasPrefix(raw, `"`) {
+		var value string
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+// recordReader reads the message records of the input file, one at a time.
+type recordReader struct {
+	decoder *json.Decoder
+	format  string
+	index   int
+}
+
+func newRecordReader(input io.Reader, format string) (*recordReader, error) {
+	decoder := json.NewDecoder(input)
+	decoder.DisallowUnknownFields()
+	if format == jsonArrayInput {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the input file: %w", err)
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, errors.New("the input file must contain a Json array of message records")
+		}
+	}
+	return &recordReader{
+		decoder: decoder,
+		format:  format,
+	}, nil
+}
+
+// next returns the next record of the input file, or io.EOF if there are no more records.
+func (r *recordReader) next() (*inputRecord, error) {
+	if r.format == jsonArrayInput && !r.decoder.More() {
+		return nil, io.EOF
+	}
+	record := &inputRecord{}
+	err := r.decoder.Decode(record)
+	if err != nil {
+		if errors.Is(err, io.EOF) && r.format == jsonLinesInput {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("invalid message record at index %d: %w", r.index, err)
+	}
+	if record.Partition != nil && *record.Partition < 0 {
+		return nil, fmt.Errorf("invalid message record at index %d: the partition cannot be negative", r.index)
+	}
+	r.index++
+	return record, nil
+}
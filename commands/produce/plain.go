@@ -3,7 +3,6 @@ package produce
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 
@@ -15,29 +14,27 @@ import (
 type plain struct {
 	kafkaParams  *commands.KafkaParameters
 	globalParams *commands.GlobalParameters
+	params       *producerParams
 	message      string
-	key          string
 	topic        string
-	count        uint64
 	parser       *template.Parser
-	random       bool
-	sleep        time.Duration
 }
 
 func addPlainSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
 	cmd := &plain{
 		kafkaParams:  kafkaParams,
 		globalParams: global,
+		params:       &producerParams{},
 		parser:       template.NewParser(),
 	}
 	c := parent.Command("plain", "Publishes plain text messages to Kafka. The content can be arbitrary text, json, base64 or hex encoded strings.").Action(cmd.run)
 	c.Arg("topic", "The topic to publish to.").Required().StringVar(&cmd.topic)
 	c.Arg("content", "The message content. You can pipe the content in, or pass it as the command's second argument.").StringVar(&cmd.message)
-	addProducerFlags(c, &cmd.sleep, &cmd.key, &cmd.random, &cmd.count)
+	bindProducerFlags(c, cmd.params)
 }
 
 func (c *plain) run(_ *kingpin.ParseContext) error {
-	value, err := getValue(c.message)
+	value, err := c.params.getValue(c.message)
 	if err != nil {
 		return err
 	}
@@ -49,11 +46,11 @@ func (c *plain) run(_ *kingpin.ParseContext) error {
 		cancel()
 	}()
 
-	return produce(ctx, c.kafkaParams, c.globalParams, c.topic, c.key, value, c.serialize, c.count, c.sleep)
+	return produce(ctx, c.kafkaParams, c.globalParams, c.topic, value, c.params, c.serialize)
 }
 
 func (c *plain) serialize(value string) ([]byte, error) {
-	if !c.random {
+	if !c.params.random {
 		return []byte(value), nil
 	}
 	value, err := c.parser.Parse(value)
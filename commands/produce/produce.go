@@ -25,60 +25,179 @@ func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, ka
 	parent := app.Command("produce", "A command to publish messages to kafka.")
 	addPlainSubCommand(parent, global, kafkaParams)
 	addProtoSubCommand(parent, global, kafkaParams)
+	addRegistrySubCommand(parent, global, kafkaParams)
 	addSchemaSubCommand(parent, global)
 }
 
-func addProducerFlags(cmd *kingpin.CmdClause, sleep *time.Duration, key *string, random *bool, count *uint64) {
+// producerParams holds the common flags of the produce commands.
+type producerParams struct {
+	key         string
+	headers     map[string]string
+	random      bool
+	partition   int32
+	count       uint64
+	countSet    bool
+	sleep       time.Duration
+	rateValue   string
+	rate        float64
+	duration    time.Duration
+	inputFile   string
+	inputFormat string
+	async       bool
+	settings    kafka.ProducerSettings
+	txnSize     uint64
+}
+
+// message represents a message to publish.
+type message struct {
+	key       string
+	value     string
+	headers   map[string]string
+	partition int32
+}
+
+// messageSource returns the next message to publish, or io.EOF if there are no more messages.
+type messageSource func() (*message, error)
+
+func bindProducerFlags(cmd *kingpin.CmdClause, params *producerParams) {
 	cmd.Flag("key", "The partition key of the message. If not set, a random value will be selected.").
 		Short('k').
-		StringVar(key)
+		StringVar(&params.key)
+	cmd.Flag("header", "The message header in key=value format. Repeat the flag to set multiple headers (eg. --header trace-id=123 --header content-type=json).").
+		Short('H').
+		PlaceHolder("KEY=VALUE").
+		StringMapVar(&params.headers)
+	cmd.Flag("partition", "The partition to publish the messages to. If not set, the partition will be selected by the partitioner.").
+		Short('p').
+		Default("-1").
+		NoEnvar().
+		Int32Var(&params.partition)
+	cmd.Flag("partitioner", "The partitioner to select the partitions of the messages based on their keys. Use murmur2 to publish to the same partitions as the Java clients.").
+		Default(kafka.HashPartitioner).
+		NoEnvar().
+		EnumVar(&params.settings.Partitioner, kafka.Partitioners...)
 	cmd.Flag("generate-random-data", "Replaces the random generator place holder functions in the content (if any) with random values.").
 		Short('g').
-		BoolVar(random)
-	cmd.Flag("count", "The number of messages to publish. Set to zero to produce indefinitely.").
+		BoolVar(&params.random)
+	cmd.Flag("count", "The number of messages to publish. Set to zero to produce indefinitely. Will be ignored if --input-file is set. Defaults to zero if --duration is set.").
 		Default("1").
 		Short('c').
-		Uint64Var(count)
+		PreAction(func(_ *kingpin.ParseContext) error {
+			params.countSet = true
+			return nil
+		}).
+		Uint64Var(&params.count)
 	cmd.Flag("sleep", "The amount of time to wait before publishing each message to Kafka. Examples 500ms, 1s, 1m or 1h5m.").
 		HintOptions("500ms", "1s", "1m").
 		Default("0").
 		Short('s').
-		DurationVar(sleep)
+		DurationVar(&params.sleep)
+	cmd.Flag("rate", "The target number of messages to publish per second (eg. 100/s), minute (eg. 600/m) or hour (eg. 5000/h). The achieved rate will be reported at the end. Cannot be used with --sleep.").
+		PlaceHolder("N/s").
+		NoEnvar().
+		StringVar(&params.rateValue)
+	cmd.Flag("duration", "The amount of time to keep publishing messages for. Examples 30s, 10m or 1h.").
+		HintOptions("30s", "10m", "1h").
+		Default("0").
+		NoEnvar().
+		DurationVar(&params.duration)
+	cmd.Flag("input-file", "The file to read the messages from. Each record can specify the key, value, headers and partition of the message (eg. {\"key\":\"k\",\"value\":{\"name\":\"trubka\"},\"headers\":{\"h\":\"v\"},\"partition\":0}). The value can be a Json string or any other Json value. The --key, --header and --partition flags are applied to the records which do not specify their own.").
+		Short('i').
+		NoEnvar().
+		ExistingFileVar(&params.inputFile)
+	cmd.Flag("input-format", fmt.Sprintf("The format of the input file. %s expects one Json record per line. %s expects a Json array of records.", jsonLinesInput, jsonArrayInput)).
+		Default(jsonLinesInput).
+		NoEnvar().
+		EnumVar(&params.inputFormat, jsonLinesInput, jsonArrayInput)
+	cmd.Flag("async", "Publishes the messages asynchronously in batches and prints a throughput and latency summary at the end. Useful for load testing.").
+		NoEnvar().
+		BoolVar(&params.async)
+	cmd.Flag("batch-size", "The number of messages which triggers sending a batch to Kafka. Zero means the batches are sent as fast as possible.").
+		Default("0").
+		NoEnvar().
+		IntVar(&params.settings.BatchSize)
+	cmd.Flag("linger", "The maximum amount of time to wait for a batch to fill up before sending it to Kafka. Examples 5ms, 100ms or 1s.").
+		HintOptions("5ms", "100ms", "1s").
+		Default("0").
+		NoEnvar().
+		DurationVar(&params.settings.Linger)
+	cmd.Flag("compression", "The compression codec of the messages.").
+		Default(kafka.CompressionCodecs[0]).
+		NoEnvar().
+		EnumVar(&params.settings.Compression, kafka.CompressionCodecs...)
+	cmd.Flag("acks", "The number of acknowledgements the leader must receive before considering a request complete.").
+		Default(kafka.AcksAll).
+		NoEnvar().
+		EnumVar(&params.settings.Acks, kafka.AcksAll, kafka.AcksLeader, kafka.AcksNone)
+	cmd.Flag("max-in-flight", "The maximum number of unacknowledged requests to send to each broker.").
+		Default("5").
+		NoEnvar().
+		IntVar(&params.settings.MaxInFlight)
+	cmd.Flag("idempotent", "Enables the idempotent producer to ensure that exactly one copy of each message is written to the log. Requires --acks all and limits the in-flight requests to one.").
+		NoEnvar().
+		BoolVar(&params.settings.Idempotent)
+	cmd.Flag("transactional-id", "Publishes the messages in transactions using the specified transactional ID. Implies --idempotent. The ongoing transaction will be aborted if publishing fails or the operation is cancelled.").
+		NoEnvar().
+		StringVar(&params.settings.TransactionalID)
+	cmd.Flag("transaction-size", "The number of messages to publish in each transaction. Set to zero to publish all the messages in a single transaction.").
+		Default("0").
+		NoEnvar().
+		Uint64Var(&params.txnSize)
 }
 
-func initialiseProducer(kafkaParams *commands.KafkaParameters, verbosity internal.VerbosityLevel) (*kafka.Producer, error) {
-
+func producerOptions(kafkaParams *commands.KafkaParameters, verbosity internal.VerbosityLevel, settings *kafka.ProducerSettings) []kafka.Option {
 	saramaLogWriter := io.Discard
 	if verbosity >= internal.Chatty {
 		saramaLogWriter = os.Stdout
 	}
 
-	brokers := commands.GetBrokers(kafkaParams.Brokers)
-	producer, err := kafka.NewProducer(
-		brokers,
+	return []kafka.Option{
 		kafka.WithClusterVersion(kafkaParams.Version),
 		kafka.WithTLS(kafkaParams.TLS),
 		kafka.WithLogWriter(saramaLogWriter),
 		kafka.WithSASL(kafkaParams.SASLMechanism,
 			kafkaParams.SASLUsername,
-			kafkaParams.SASLPassword,
-			kafkaParams.SASLHandshakeVersion))
-
-	if err != nil {
-		return nil, err
+			kafkaParams.Password(),
+			kafkaParams.SASLHandshakeVersion,
+			kafkaParams.SASLOptions()...),
+		kafka.WithProducerSettings(settings),
 	}
-	return producer, nil
 }
 
 func produce(ctx context.Context,
 	kafkaParams *commands.KafkaParameters,
 	globalParams *commands.GlobalParameters,
 	topic string,
-	key, value string,
-	serialize valueSerializer,
-	count uint64,
-	sleep time.Duration) error {
-	producer, err := initialiseProducer(kafkaParams, globalParams.Verbosity)
+	value string,
+	params *producerParams,
+	serialize valueSerializer) error {
+	err := params.validate()
+	if err != nil {
+		return err
+	}
+
+	next, closeSource, err := newMessageSource(value, params)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
+	if params.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.duration)
+		defer cancel()
+	}
+
+	if globalParams.Verbosity >= internal.Verbose {
+		printPublishingMessage(params)
+	}
+
+	if params.async {
+		return produceAsync(ctx, kafkaParams, globalParams, topic, params, next, serialize)
+	}
+
+	brokers := commands.GetBrokers(kafkaParams.Brokers)
+	producer, err := kafka.NewProducer(brokers, producerOptions(kafkaParams, globalParams.Verbosity, &params.settings)...)
 	if err != nil {
 		return err
 	}
@@ -93,57 +212,197 @@ func produce(ctx context.Context,
 		}
 	}()
 
-	if globalParams.Verbosity >= internal.Verbose {
-		msg := "message"
-		switch {
-		case count == 0:
-			msg = "indefinite number of messages"
-		case count == 1:
-			msg = "a single message"
-		case count > 1:
-			msg = fmt.Sprintf("%d messages", count)
-		}
-		fmt.Printf("Publishing %s to Kafka\n", msg)
+	summary := newDeliverySummary(params.rate)
+	start := time.Now()
+	err = produceSync(ctx, producer, globalParams, topic, params, next, serialize, summary)
+	summary.elapsed = time.Since(start)
+	if params.rate > 0 {
+		summary.print(globalParams.EnableColor)
 	}
+	return err
+}
 
-	randomPk := len(key) == 0
-	counter := uint64(1)
-	capped := count > 0
-	mustSleep := sleep > 0 && (count == 0 || count > 1)
+func produceSync(ctx context.Context,
+	producer *kafka.Producer,
+	globalParams *commands.GlobalParameters,
+	topic string,
+	params *producerParams,
+	next messageSource,
+	serialize valueSerializer,
+	summary *deliverySummary) (err error) {
+	throttle := newThrottler(params, globalParams.Verbosity)
+	txn := newTransaction(producer, params.txnSize, globalParams.Verbosity)
+	defer func() {
+		err = txn.close(ctx, err)
+	}()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		default:
-			if randomPk {
-				key = fmt.Sprintf("%d%d", time.Now().UnixNano(), counter)
+			msg, err := next()
+			if errors.Is(err, io.EOF) {
+				return nil
 			}
-			vBytes, err := serialize(value)
 			if err != nil {
 				return err
 			}
-			partition, offset, err := producer.Produce(topic, []byte(key), vBytes)
+			if throttle.wait(ctx) != nil {
+				return nil
+			}
+			vBytes, err := serialize(msg.value)
+			if err != nil {
+				return err
+			}
+			if err := txn.begin(); err != nil {
+				return err
+			}
+			sent := time.Now()
+			partition, offset, err := producer.Produce(topic, []byte(msg.key), vBytes, msg.headers, msg.partition)
 			if err != nil {
 				return fmt.Errorf("failed to publish to kafka: %w", err)
 			}
+			summary.add(&kafka.DeliveryReport{
+				Partition: partition,
+				Offset:    offset,
+				Latency:   time.Since(sent),
+			})
 			if globalParams.Verbosity >= internal.VeryVerbose {
 				fmt.Printf("Message has been published to the offset %d of partition %d (PK: %s).\n",
 					offset,
 					partition,
-					key)
+					msg.key)
 			}
-			if capped && counter >= count {
-				return nil
+			if err := txn.add(); err != nil {
+				return err
 			}
-			counter++
-			if mustSleep {
-				if globalParams.Verbosity >= internal.SuperVerbose {
-					fmt.Printf("Waiting for %s before producing the next message.\n", sleep)
-				}
-				time.Sleep(sleep)
+		}
+	}
+}
+
+func printPublishingMessage(params *producerParams) {
+	msg := "message"
+	switch {
+	case !internal.IsEmpty(params.inputFile):
+		msg = "the messages of " + params.inputFile
+	case params.count == 0:
+		msg = "indefinite number of messages"
+	case params.count == 1:
+		msg = "a single message"
+	case params.count > 1:
+		msg = fmt.Sprintf("%d messages", params.count)
+	}
+	fmt.Printf("Publishing %s to Kafka\n", msg)
+}
+
+// newMessageSource creates a source which either reads the messages from the input file, or repeats the same value
+// as many times as requested.
+func newMessageSource(value string, params *producerParams) (messageSource, func(), error) {
+	var counter uint64
+	nextKey := func(key string) string {
+		counter++
+		if len(key) == 0 {
+			return fmt.Sprintf("%d%d", time.Now().UnixNano(), counter)
+		}
+		return key
+	}
+
+	if internal.IsEmpty(params.inputFile) {
+		return func() (*message, error) {
+			if params.count > 0 && counter >= params.count {
+				return nil, io.EOF
 			}
+			return &message{
+				key:       nextKey(params.key),
+				value:     value,
+				headers:   params.headers,
+				partition: params.partition,
+			}, nil
+		}, func() {}, nil
+	}
+
+	file, err := os.Open(params.inputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open the input file: %w", err)
+	}
+	reader, err := newRecordReader(file, params.inputFormat)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return func() (*message, error) {
+			record, err := reader.next()
+			if err != nil {
+				return nil, err
+			}
+			content, err := record.content()
+			if err != nil {
+				return nil, fmt.Errorf("invalid message record at index %d: %w", reader.index-1, err)
+			}
+			msg := &message{
+				value:     content,
+				headers:   params.headers,
+				partition: params.partition,
+			}
+			// An explicit key in the record, even if empty, must be produced as is.
+			if record.Key != nil {
+				msg.key = *record.Key
+			} else {
+				msg.key = nextKey(params.key)
+			}
+			if len(record.Headers) > 0 {
+				msg.headers = record.Headers
+			}
+			if record.Partition != nil {
+				msg.partition = *record.Partition
+			}
+			return msg, nil
+		}, func() {
+			_ = file.Close()
+		}, nil
+}
+
+// validate validates the flags and resolves the rate and the count of the messages.
+func (p *producerParams) validate() error {
+	if p.partition < -1 {
+		return fmt.Errorf("invalid partition %d", p.partition)
+	}
+	if p.txnSize > 0 && internal.IsEmpty(p.settings.TransactionalID) {
+		return errors.New("--transaction-size can only be used with --transactional-id")
+	}
+	if p.async && !internal.IsEmpty(p.settings.TransactionalID) {
+		return errors.New("transactions are not supported in async mode")
+	}
+	if p.duration < 0 {
+		return errors.New("the duration cannot be negative")
+	}
+	if !internal.IsEmpty(p.rateValue) {
+		if p.sleep > 0 {
+			return errors.New("--sleep and --rate cannot be used together")
 		}
+		rate, err := internal.ParseRate(p.rateValue)
+		if err != nil {
+			return err
+		}
+		p.rate = rate
+	}
+	if p.duration > 0 && !p.countSet {
+		p.count = 0
+	}
+	return nil
+}
+
+// getValue returns the message content from the command argument or the shell pipe.
+//
+// The content must be empty if the messages are read from the input file.
+func (p *producerParams) getValue(flagValue string) (string, error) {
+	if internal.IsEmpty(p.inputFile) {
+		return getValue(flagValue)
+	}
+	if !internal.IsEmpty(flagValue) {
+		return "", errors.New("the message content cannot be set when the messages are read from the input file")
 	}
+	return "", nil
 }
 
 func getValue(flagValue string) (string, error) {
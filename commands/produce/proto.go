@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/jhump/protoreflect/dynamic"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -20,19 +19,16 @@ import (
 type proto struct {
 	kafkaParams    *commands.KafkaParameters
 	globalParams   *commands.GlobalParameters
+	params         *producerParams
 	message        string
-	key            string
 	topic          string
 	proto          string
-	count          uint64
 	protoRoot      string
-	random         bool
 	protoMessage   *dynamic.Message
 	highlightStyle string
 	highlighter    *internal.JSONHighlighter
 	decodeFrom     string
 	parser         *template.Parser
-	sleep          time.Duration
 }
 
 func addProtoSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
@@ -40,6 +36,7 @@ func addProtoSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParame
 	cmd := &proto{
 		kafkaParams:  kafkaParams,
 		globalParams: global,
+		params:       &producerParams{},
 		parser:       template.NewParser(),
 	}
 	c := parent.Command("proto", "Publishes protobuf messages to Kafka.").Action(cmd.run)
@@ -50,11 +47,10 @@ func addProtoSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParame
 		Short('D').
 		Default(internal.JSONEncoding).
 		EnumVar(&cmd.decodeFrom, internal.JSONEncoding, internal.Base64Encoding, internal.HexEncoding)
-	c.Flag("proto-root", "The path to the folder where your *.proto files live.").
+	c.Flag("proto-root", "The path to the folder where your *.proto files live (Default: the proto root of the active profile).").
 		Short('r').
-		Required().
 		StringVar(&cmd.protoRoot)
-	addProducerFlags(c, &cmd.sleep, &cmd.key, &cmd.random, &cmd.count)
+	bindProducerFlags(c, cmd.params)
 	c.Flag("style", fmt.Sprintf("The highlighting style of the Json message content. Applicable to --content-type=%s only. Set to 'none' to disable.", internal.JSONEncoding)).
 		Default(internal.DefaultHighlightStyle).
 		EnumVar(&cmd.highlightStyle,
@@ -62,7 +58,11 @@ func addProtoSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParame
 }
 
 func (c *proto) run(_ *kingpin.ParseContext) error {
-	value, err := getValue(c.message)
+	if internal.IsEmpty(c.protoRoot) {
+		return commands.ErrMissingProtoRoot
+	}
+
+	value, err := c.params.getValue(c.message)
 	if err != nil {
 		return err
 	}
@@ -92,7 +92,7 @@ func (c *proto) run(_ *kingpin.ParseContext) error {
 	c.protoMessage = message
 	c.highlighter = internal.NewJSONHighlighter(c.highlightStyle, c.globalParams.EnableColor)
 
-	return produce(ctx, c.kafkaParams, c.globalParams, c.topic, c.key, value, c.serializeProto, c.count, c.sleep)
+	return produce(ctx, c.kafkaParams, c.globalParams, c.topic, value, c.params, c.serializeProto)
 }
 
 func (c *proto) serializeProto(value string) (result []byte, err error) {
@@ -105,7 +105,7 @@ func (c *proto) serializeProto(value string) (result []byte, err error) {
 		result, err = hex.DecodeString(value)
 	default:
 		isJSON = true
-		if c.random {
+		if c.params.random {
 			value, err = c.parser.Parse(value)
 			if err != nil {
 				return nil, err
@@ -114,7 +114,7 @@ func (c *proto) serializeProto(value string) (result []byte, err error) {
 
 		err = c.protoMessage.UnmarshalJSON([]byte(value))
 		if err != nil {
-			if !c.random {
+			if !c.params.random {
 				return nil, fmt.Errorf("failed to parse the input as json. If the schema has been produced using -g flag, you must use the same flag (-g) to enable template parsing when publishing to Kafka: %w", err)
 			}
 			return nil, err
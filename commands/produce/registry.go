@@ -0,0 +1,117 @@
+package produce
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/commands/produce/template"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/schemaregistry"
+)
+
+type registry struct {
+	kafkaParams    *commands.KafkaParameters
+	globalParams   *commands.GlobalParameters
+	params         *producerParams
+	registryParams *commands.RegistryParameters
+	message        string
+	topic          string
+	subject        string
+	schemaID       int
+	messageType    string
+	highlightStyle string
+	highlighter    *internal.JSONHighlighter
+	parser         *template.Parser
+	ctx            context.Context
+	serde          *schemaregistry.Serde
+	schema         *schemaregistry.Schema
+}
+
+func addRegistrySubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &registry{
+		kafkaParams:    kafkaParams,
+		globalParams:   global,
+		params:         &producerParams{},
+		registryParams: &commands.RegistryParameters{},
+		parser:         template.NewParser(),
+	}
+	c := parent.Command("registry", "Publishes Avro, Protobuf or Json Schema messages to Kafka, using the Schema Registry wire format.").Action(cmd.run)
+	c.Arg("topic", "The topic to publish to.").Required().StringVar(&cmd.topic)
+	c.Arg("content", "The JSON representation of the message. You can pipe the content in, or pass it as the command's second argument.").StringVar(&cmd.message)
+	commands.BindRegistryFlags(c, cmd.registryParams)
+	c.Flag("subject", "The subject of which the latest schema version will be used to serialise the message. The default value is <topic>-value.").
+		StringVar(&cmd.subject)
+	c.Flag("schema-id", "The ID of the schema to serialise the message with. If set, the --subject flag will be ignored.").
+		IntVar(&cmd.schemaID)
+	c.Flag("message-type", "The fully qualified name of the protobuf message type. Applicable to Protobuf schemas only. The default value is the first message type defined in the schema.").
+		StringVar(&cmd.messageType)
+	bindProducerFlags(c, cmd.params)
+	c.Flag("style", "The highlighting style of the Json message content. Set to 'none' to disable.").
+		Default(internal.DefaultHighlightStyle).
+		EnumVar(&cmd.highlightStyle,
+			internal.HighlightStyles...)
+}
+
+func (c *registry) run(_ *kingpin.ParseContext) error {
+	value, err := c.params.getValue(c.message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		internal.WaitForCancellationSignal()
+		cancel()
+	}()
+
+	client, err := commands.NewRegistryClient(c.registryParams)
+	if err != nil {
+		return err
+	}
+
+	if c.schemaID > 0 {
+		c.schema, err = client.SchemaByID(ctx, c.schemaID)
+	} else {
+		if internal.IsEmpty(c.subject) {
+			c.subject = c.topic + "-value"
+		}
+		c.schema, err = client.LatestSchema(ctx, c.subject)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.globalParams.Verbosity >= internal.VeryVerbose {
+		fmt.Printf("Serialising the messages using the %s schema with ID %d.\n", c.schema.Type, c.schema.ID)
+	}
+
+	c.ctx = ctx
+	c.serde = schemaregistry.NewSerde(client)
+	c.highlighter = internal.NewJSONHighlighter(c.highlightStyle, c.globalParams.EnableColor)
+
+	return produce(ctx, c.kafkaParams, c.globalParams, c.topic, value, c.params, c.serialize)
+}
+
+func (c *registry) serialize(value string) ([]byte, error) {
+	if c.params.random {
+		var err error
+		value, err = c.parser.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := c.serde.Encode(c.ctx, c.schema, c.messageType, []byte(value))
+	if err != nil {
+		return nil, err
+	}
+
+	if c.globalParams.Verbosity >= internal.Verbose {
+		fmt.Printf("%s\n", c.highlighter.Highlight([]byte(value)))
+	}
+	return result, nil
+}
@@ -36,9 +36,8 @@ func addSchemaSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParam
 	}
 	c := parent.Command("schema", "Produces the JSON representation of the given proto message. The produced schema can be used to publish to Kafka.").Action(cmd.run)
 	c.Arg("proto", "The fully qualified name of the proto message to generate the JSON schema of.").Required().StringVar(&cmd.proto)
-	c.Flag("proto-root", "The path to the folder where your *.proto files live.").
+	c.Flag("proto-root", "The path to the folder where your *.proto files live (Default: the proto root of the active profile).").
 		Short('r').
-		Required().
 		StringVar(&cmd.protoRoot)
 	c.Flag("random-generators", "Use random generator functions for each field instead of default values.").
 		Short('g').
@@ -46,6 +45,10 @@ func addSchemaSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParam
 }
 
 func (c *schema) run(_ *kingpin.ParseContext) error {
+	if internal.IsEmpty(c.protoRoot) {
+		return commands.ErrMissingProtoRoot
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go func() {
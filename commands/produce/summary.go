@@ -0,0 +1,81 @@
+package produce
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output/format"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+// deliverySummary holds the throughput and latency statistics of the published messages.
+type deliverySummary struct {
+	published uint64
+	failed    uint64
+	latencies []time.Duration
+	elapsed   time.Duration
+	target    float64
+}
+
+func newDeliverySummary(target float64) *deliverySummary {
+	return &deliverySummary{
+		latencies: make([]time.Duration, 0),
+		target:    target,
+	}
+}
+
+func (s *deliverySummary) add(report *kafka.DeliveryReport) {
+	if report.Err != nil {
+		s.failed++
+		return
+	}
+	s.published++
+	s.latencies = append(s.latencies, report.Latency)
+}
+
+func (s *deliverySummary) throughput() float64 {
+	if s.elapsed <= 0 {
+		return 0
+	}
+	return float64(s.published) / s.elapsed.Seconds()
+}
+
+func (s *deliverySummary) print(highlight bool) {
+	columns := []*tabular.Column{
+		tabular.C("Published"),
+		tabular.C("Failed"),
+		tabular.C("Duration"),
+	}
+	if s.target > 0 {
+		columns = append(columns, tabular.C("Target (msg/s)"))
+	}
+	columns = append(columns,
+		tabular.C("Throughput (msg/s)"),
+		tabular.C("P50 Latency"),
+		tabular.C("P99 Latency"))
+	table := tabular.NewTable(highlight, columns...)
+
+	failed := format.RedIfTrue(humanize.Comma(int64(s.failed)), func() bool {
+		return s.failed > 0
+	}, highlight)
+
+	row := []interface{}{
+		humanize.Comma(int64(s.published)),
+		failed,
+		s.elapsed.Round(time.Millisecond),
+	}
+	if s.target > 0 {
+		row = append(row, humanize.CommafWithDigits(s.target, 2))
+	}
+	row = append(row,
+		humanize.CommafWithDigits(s.throughput(), 2),
+		internal.Percentile(s.latencies, 50).Round(time.Microsecond),
+		internal.Percentile(s.latencies, 99).Round(time.Microsecond))
+	table.AddRow(row...)
+	table.SetTitle("SUMMARY")
+	table.TitleAlignment(tabular.AlignCenter)
+	table.Render()
+}
@@ -0,0 +1,58 @@
+package produce
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xitonix/trubka/internal"
+)
+
+// throttler controls the pace at which the messages are published.
+//
+// The messages are either published at a steady rate, or with a fixed delay in between.
+type throttler struct {
+	sleep     time.Duration
+	bucket    *internal.TokenBucket
+	verbosity internal.VerbosityLevel
+	started   bool
+}
+
+func newThrottler(params *producerParams, verbosity internal.VerbosityLevel) *throttler {
+	t := &throttler{
+		sleep:     params.sleep,
+		verbosity: verbosity,
+	}
+	if params.rate > 0 {
+		// Allowing up to 10ms worth of messages to be published in a burst, so that the timer
+		// resolution does not cap the achievable rate.
+		t.bucket = internal.NewTokenBucket(params.rate, int(math.Ceil(params.rate/100)))
+	}
+	return t
+}
+
+// wait blocks until the next message can be published, or the context is cancelled.
+func (t *throttler) wait(ctx context.Context) error {
+	if t.bucket != nil {
+		return t.bucket.Wait(ctx)
+	}
+	if !t.started {
+		t.started = true
+		return nil
+	}
+	if t.sleep <= 0 {
+		return nil
+	}
+	if t.verbosity >= internal.SuperVerbose {
+		fmt.Printf("Waiting for %s before producing the next message.\n", t.sleep)
+	}
+	timer := time.NewTimer(t.sleep)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
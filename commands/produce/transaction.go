@@ -0,0 +1,93 @@
+package produce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/kafka"
+)
+
+// transaction groups the published messages into transactions of the requested size.
+//
+// All the methods are no-op if the producer is not transactional.
+type transaction struct {
+	producer  *kafka.Producer
+	size      uint64
+	count     uint64
+	open      bool
+	verbosity internal.VerbosityLevel
+}
+
+func newTransaction(producer *kafka.Producer, size uint64, verbosity internal.VerbosityLevel) *transaction {
+	return &transaction{
+		producer:  producer,
+		size:      size,
+		verbosity: verbosity,
+	}
+}
+
+// begin starts a new transaction, if there is no transaction in progress.
+func (t *transaction) begin() error {
+	if t.open || !t.producer.IsTransactional() {
+		return nil
+	}
+	err := t.producer.BeginTransaction()
+	if err != nil {
+		return err
+	}
+	t.open = true
+	t.count = 0
+	if t.verbosity >= internal.VeryVerbose {
+		fmt.Println("A new transaction has been started.")
+	}
+	return nil
+}
+
+// add registers a published message and commits the transaction once it reaches the requested size.
+func (t *transaction) add() error {
+	if !t.open {
+		return nil
+	}
+	t.count++
+	if t.size > 0 && t.count >= t.size {
+		return t.commit()
+	}
+	return nil
+}
+
+// close commits the ongoing transaction, unless publishing has failed or the user has cancelled the operation.
+func (t *transaction) close(ctx context.Context, err error) error {
+	if !t.open {
+		return err
+	}
+	if err != nil || errors.Is(ctx.Err(), context.Canceled) {
+		return errors.Join(err, t.abort())
+	}
+	return t.commit()
+}
+
+func (t *transaction) commit() error {
+	t.open = false
+	err := t.producer.CommitTransaction()
+	if err != nil {
+		return err
+	}
+	if t.verbosity >= internal.Verbose {
+		fmt.Printf("The transaction of %d messages has been committed.\n", t.count)
+	}
+	return nil
+}
+
+func (t *transaction) abort() error {
+	t.open = false
+	err := t.producer.AbortTransaction()
+	if err != nil {
+		return err
+	}
+	if t.verbosity >= internal.Verbose {
+		fmt.Printf("The transaction of %d messages has been aborted.\n", t.count)
+	}
+	return nil
+}
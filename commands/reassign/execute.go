@@ -0,0 +1,85 @@
+package reassign
+
+import (
+	"fmt"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+)
+
+type execute struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	planFile     string
+	rollbackFile string
+	silent       bool
+	format       string
+	style        string
+}
+
+func addExecuteSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &execute{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("execute", "Starts reassigning the partitions based on a Json plan.").Action(cmd.run)
+	c.Arg("plan", "The path to the Json reassignment plan, in the format generated by the generate command or the kafka-reassign-partitions tool.").
+		Required().
+		ExistingFileVar(&cmd.planFile)
+	c.Flag("rollback-file", "The file to export the current replica assignments to, before executing the plan. The file can be used to roll back the changes.").
+		NoEnvar().
+		StringVar(&cmd.rollbackFile)
+	c.Flag("silent", "Executes the plan without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (e *execute) run(_ *kingpin.ParseContext) error {
+	plan, err := readPlan(e.planFile)
+	if err != nil {
+		return err
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(e.globalParams, e.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	current, err := manager.GetReplicaAssignments(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	err = printChanges(current, plan, e.format, e.style, e.globalParams.EnableColor)
+	if err != nil {
+		return err
+	}
+
+	if !e.silent && !commands.AskForConfirmation("Are you sure you want to reassign the partitions") {
+		return nil
+	}
+
+	if !internal.IsEmpty(e.rollbackFile) {
+		err = writePlan(e.rollbackFile, current)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The current replica assignments have been exported to %s.\n", e.rollbackFile)
+	}
+
+	err = manager.ExecuteReassignmentPlan(ctx, plan)
+	if err != nil {
+		return err
+	}
+	fmt.Println("The partition reassignments have been started successfully. Use the status command to check the progress.")
+	return nil
+}
@@ -0,0 +1,100 @@
+package reassign
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+)
+
+type generate struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topics       []string
+	brokerIDs    string
+	outputFile   string
+	format       string
+	style        string
+}
+
+func addGenerateSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &generate{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("generate", "Generates a plan to distribute the replicas of the topics evenly across the specified brokers.").Action(cmd.run)
+	c.Flag("topic", "The topic to reassign. Repeat the flag to reassign multiple topics.").
+		Short('t').
+		Required().
+		NoEnvar().
+		StringsVar(&cmd.topics)
+	c.Flag("broker-ids", "The comma separated list of the IDs of the brokers to assign the replicas to (eg. 1,2,3).").
+		Required().
+		NoEnvar().
+		StringVar(&cmd.brokerIDs)
+	c.Flag("output-file", "The file to export the proposed plan to. The file can be used by the execute command, or by the kafka-reassign-partitions tool.").
+		Short('o').
+		NoEnvar().
+		StringVar(&cmd.outputFile)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (g *generate) run(_ *kingpin.ParseContext) error {
+	brokerIDs, err := parseBrokerIDs(g.brokerIDs)
+	if err != nil {
+		return err
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(g.globalParams, g.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	current, proposed, err := manager.GetReassignmentPlan(ctx, g.topics, brokerIDs)
+	if err != nil {
+		return err
+	}
+
+	err = printChanges(current, proposed, g.format, g.style, g.globalParams.EnableColor)
+	if err != nil {
+		return err
+	}
+
+	if !internal.IsEmpty(g.outputFile) {
+		err = writePlan(g.outputFile, proposed)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The proposed plan has been exported to %s.\n", g.outputFile)
+	}
+	return nil
+}
+
+func parseBrokerIDs(commaSeparated string) ([]int32, error) {
+	result := make([]int32, 0)
+	for _, value := range strings.Split(commaSeparated, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid broker ID %q. The broker ID must be an integer", value)
+		}
+		result = append(result, int32(id))
+	}
+	if len(result) == 0 {
+		return nil, errors.New("the broker IDs cannot be empty")
+	}
+	return result, nil
+}
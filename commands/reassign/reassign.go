@@ -0,0 +1,115 @@
+package reassign
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+// AddCommands adds the reassign command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	parent := app.Command("reassign", "A command to reassign topic partitions to Kafka brokers.")
+	addGenerateSubCommand(parent, global, kafkaParams)
+	addExecuteSubCommand(parent, global, kafkaParams)
+	addStatusSubCommand(parent, global, kafkaParams)
+}
+
+func readPlan(path string) (*kafka.ReassignmentPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the reassignment plan: %w", err)
+	}
+	return kafka.ParseReassignmentPlan(data)
+}
+
+func writePlan(path string, plan *kafka.ReassignmentPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write the reassignment plan: %w", err)
+	}
+	return nil
+}
+
+func printChanges(current, proposed *kafka.ReassignmentPlan, format, style string, enableColor bool) error {
+	switch format {
+	case commands.JSONFormat:
+		data := struct {
+			Current  *kafka.ReassignmentPlan `json:"current"`
+			Proposed *kafka.ReassignmentPlan `json:"proposed"`
+		}{
+			Current:  current,
+			Proposed: proposed,
+		}
+		return output.PrintAsJSON(data, style, enableColor)
+	case commands.TableFormat:
+		printChangesAsTable(current, proposed, enableColor)
+	case commands.TreeFormat:
+		printChangesAsList(current, proposed, false)
+	case commands.PlainTextFormat:
+		printChangesAsList(current, proposed, true)
+	}
+	return nil
+}
+
+func printChangesAsTable(current, proposed *kafka.ReassignmentPlan, enableColor bool) {
+	table := tabular.NewTable(enableColor,
+		tabular.C("Topic").Align(tabular.AlignLeft),
+		tabular.C("Partition").MinWidth(10),
+		tabular.C("Current Replicas").MinWidth(20).Align(tabular.AlignLeft),
+		tabular.C("Proposed Replicas").MinWidth(20).Align(tabular.AlignLeft),
+	)
+	table.SetTitle("Partition Reassignments")
+	for _, p := range proposed.Partitions {
+		table.AddRow(
+			p.Topic,
+			strconv.FormatInt(int64(p.Partition), 10),
+			replicasString(current.Replicas(p.Topic, p.Partition)),
+			replicasString(p.Replicas),
+		)
+	}
+	table.AddFooter(" ", " ", " ", fmt.Sprintf("Total: %d", len(proposed.Partitions)))
+	table.Render()
+}
+
+func printChangesAsList(current, proposed *kafka.ReassignmentPlan, plain bool) {
+	l := list.New(plain)
+	var topic string
+	for _, p := range proposed.Partitions {
+		if p.Topic != topic {
+			if topic != "" {
+				l.UnIndent()
+			}
+			topic = p.Topic
+			l.AddItem(topic)
+			l.Indent()
+		}
+		l.AddItemF("P%d", p.Partition)
+		l.Indent()
+		l.AddItemF(" Current: %s", replicasString(current.Replicas(p.Topic, p.Partition)))
+		l.AddItemF("Proposed: %s", replicasString(p.Replicas))
+		l.UnIndent()
+	}
+	l.Render()
+}
+
+func replicasString(replicas []int32) string {
+	ids := make([]string, len(replicas))
+	for i, id := range replicas {
+		ids[i] = strconv.FormatInt(int64(id), 10)
+	}
+	return strings.Join(ids, ", ")
+}
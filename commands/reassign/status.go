@@ -0,0 +1,153 @@
+package reassign
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type status struct {
+	globalParams *commands.GlobalParameters
+	kafkaParams  *commands.KafkaParameters
+	topics       []string
+	planFile     string
+	watch        bool
+	interval     time.Duration
+	format       string
+	style        string
+}
+
+func addStatusSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &status{
+		globalParams: global,
+		kafkaParams:  kafkaParams,
+	}
+	c := parent.Command("status", "Lists the ongoing partition reassignments.").Action(cmd.run)
+	c.Flag("topic", "The topic to check the reassignments of. Repeat the flag to check multiple topics. All the topics will be checked if not specified.").
+		Short('t').
+		NoEnvar().
+		StringsVar(&cmd.topics)
+	c.Flag("plan-file", "The Json reassignment plan to check the topics of.").
+		NoEnvar().
+		ExistingFileVar(&cmd.planFile)
+	c.Flag("watch", "Keeps polling the server until all the reassignments have been completed.").
+		Short('w').
+		NoEnvar().
+		BoolVar(&cmd.watch)
+	c.Flag("interval", "The polling interval in watch mode.").
+		Default("5s").
+		NoEnvar().
+		DurationVar(&cmd.interval)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (s *status) run(_ *kingpin.ParseContext) error {
+	if !internal.IsEmpty(s.planFile) {
+		plan, err := readPlan(s.planFile)
+		if err != nil {
+			return err
+		}
+		s.topics = append(s.topics, plan.Topics()...)
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(s.globalParams, s.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	for {
+		statuses, err := manager.GetReassignmentStatus(ctx, s.topics)
+		if err != nil {
+			return err
+		}
+
+		if len(statuses) == 0 {
+			fmt.Println("There are no ongoing partition reassignments.")
+			return nil
+		}
+
+		err = s.print(statuses)
+		if err != nil || !s.watch {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.interval):
+			output.NewLines(1)
+		}
+	}
+}
+
+func (s *status) print(statuses []*kafka.ReassignmentStatus) error {
+	switch s.format {
+	case commands.JSONFormat:
+		return output.PrintAsJSON(statuses, s.style, s.globalParams.EnableColor)
+	case commands.TableFormat:
+		s.printAsTable(statuses)
+	case commands.TreeFormat:
+		s.printAsList(statuses, false)
+	case commands.PlainTextFormat:
+		s.printAsList(statuses, true)
+	}
+	return nil
+}
+
+func (s *status) printAsTable(statuses []*kafka.ReassignmentStatus) {
+	table := tabular.NewTable(s.globalParams.EnableColor,
+		tabular.C("Topic").Align(tabular.AlignLeft),
+		tabular.C("Partition").MinWidth(10),
+		tabular.C("Replicas").MinWidth(20).Align(tabular.AlignLeft),
+		tabular.C("Adding").MinWidth(10).Align(tabular.AlignLeft),
+		tabular.C("Removing").MinWidth(10).Align(tabular.AlignLeft),
+	)
+	table.SetTitle(fmt.Sprintf("Ongoing Reassignments (%s)", time.Now().Format(time.RFC3339)))
+	for _, st := range statuses {
+		table.AddRow(
+			st.Topic,
+			strconv.FormatInt(int64(st.Partition), 10),
+			replicasString(st.Replicas),
+			replicasString(st.Adding),
+			replicasString(st.Removing),
+		)
+	}
+	table.AddFooter(" ", " ", " ", " ", fmt.Sprintf("Total: %d", len(statuses)))
+	table.Render()
+}
+
+func (s *status) printAsList(statuses []*kafka.ReassignmentStatus, plain bool) {
+	l := list.New(plain)
+	var topic string
+	for _, st := range statuses {
+		if st.Topic != topic {
+			if topic != "" {
+				l.UnIndent()
+			}
+			topic = st.Topic
+			l.AddItem(topic)
+			l.Indent()
+		}
+		l.AddItemF("P%d", st.Partition)
+		l.Indent()
+		l.AddItemF("Replicas: %s", replicasString(st.Replicas))
+		l.AddItemF("  Adding: %s", replicasString(st.Adding))
+		l.AddItemF("Removing: %s", replicasString(st.Removing))
+		l.UnIndent()
+	}
+	l.Render()
+}
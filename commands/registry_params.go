@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/schemaregistry"
+)
+
+// RegistryParameters holds CLI parameters to connect to the Schema Registry.
+type RegistryParameters struct {
+	// URL the Schema Registry URL.
+	URL string
+	// Username Schema Registry basic authentication username.
+	Username string
+	// Password Schema Registry basic authentication password.
+	Password string
+	// Timeout Schema Registry request timeout.
+	Timeout time.Duration
+}
+
+// BindRegistryFlags binds the Schema Registry connection flags to the command.
+func BindRegistryFlags(c *kingpin.CmdClause, params *RegistryParameters) {
+	c.Flag("registry-url", "The Schema Registry URL (eg. http://localhost:8081).").
+		Required().
+		StringVar(&params.URL)
+	c.Flag("registry-username", "The Schema Registry basic authentication username.").
+		StringVar(&params.Username)
+	c.Flag("registry-password", "The Schema Registry basic authentication password.").
+		StringVar(&params.Password)
+	c.Flag("registry-timeout", "The Schema Registry request timeout.").
+		Default("10s").
+		DurationVar(&params.Timeout)
+}
+
+// NewRegistryClient creates a new Schema Registry client.
+func NewRegistryClient(params *RegistryParameters) (*schemaregistry.Client, error) {
+	return schemaregistry.NewClient(params.URL, params.Username, params.Password, params.Timeout)
+}
@@ -0,0 +1,217 @@
+package reset
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/araddon/dateparse"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/output"
+	"github.com/xitonix/trubka/internal/output/format/list"
+	"github.com/xitonix/trubka/internal/output/format/tabular"
+	"github.com/xitonix/trubka/kafka"
+)
+
+type groupOffsets struct {
+	kafkaParams  *commands.KafkaParameters
+	globalParams *commands.GlobalParameters
+	group        string
+	topics       []string
+	toEarliest   bool
+	toLatest     bool
+	toDatetime   string
+	shiftBy      int64
+	toOffset     []string
+	dryRun       bool
+	silent       bool
+	format       string
+	style        string
+}
+
+func addResetGroupOffsetsSubCommand(parent *kingpin.CmdClause, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	cmd := &groupOffsets{
+		kafkaParams:  kafkaParams,
+		globalParams: global,
+	}
+	c := parent.Command("group-offsets", "Resets the offsets of a consumer group. The group must be empty (no active members).").Action(cmd.run)
+	c.Arg("group", "The consumer group to reset the offsets of.").Required().StringVar(&cmd.group)
+	c.Flag("topic", "The topic to reset the offsets of. Repeat the flag to reset multiple topics. If not set, the offsets of all the topics with committed offsets will be reset.").
+		Short('t').
+		NoEnvar().
+		StringsVar(&cmd.topics)
+	c.Flag("to-earliest", "Resets the offsets to the earliest available offset of each partition.").
+		NoEnvar().
+		BoolVar(&cmd.toEarliest)
+	c.Flag("to-latest", "Resets the offsets to the latest available offset of each partition.").
+		NoEnvar().
+		BoolVar(&cmd.toLatest)
+	c.Flag("to-datetime", "Resets the offsets to the first message at or after the specified time (eg. 2020-07-15T14:24:23+10:00).").
+		NoEnvar().
+		StringVar(&cmd.toDatetime)
+	c.Flag("shift-by", "Moves the current offsets forward (positive) or backward (negative) by the specified number of messages.").
+		NoEnvar().
+		Int64Var(&cmd.shiftBy)
+	c.Flag("to-offset", "Resets the offsets to the specified value, using the same syntax as the --from flag of the consume commands. Use Partition#Offset to reset a single partition. Repeat the flag to reset multiple partitions.").
+		PlaceHolder("[PARTITION#]OFFSET").
+		NoEnvar().
+		StringsVar(&cmd.toOffset)
+	c.Flag("dry-run", "Prints the new offsets without committing them to the server.").
+		NoEnvar().
+		BoolVar(&cmd.dryRun)
+	c.Flag("silent", "Resets the offsets without user confirmation.").
+		Short('s').
+		NoEnvar().
+		BoolVar(&cmd.silent)
+	commands.AddFormatFlag(c, &cmd.format, &cmd.style)
+}
+
+func (g *groupOffsets) run(_ *kingpin.ParseContext) error {
+	checkpoints, err := g.getCheckpoints()
+	if err != nil {
+		return err
+	}
+
+	manager, ctx, cancel, err := commands.InitKafkaManager(g.globalParams, g.kafkaParams)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		manager.Close()
+		cancel()
+	}()
+
+	offsets, err := manager.GetGroupOffsetsReset(ctx, g.group, g.topics, checkpoints, g.shiftBy)
+	if err != nil {
+		return err
+	}
+
+	if len(offsets) == 0 {
+		return fmt.Errorf("%s group has no committed offsets. Use --topic to specify the topics to reset", g.group)
+	}
+
+	switch g.format {
+	case commands.JSONFormat:
+		err = output.PrintAsJSON(offsets.ToJSON(), g.style, g.globalParams.EnableColor)
+	case commands.TableFormat:
+		g.printAsTable(offsets)
+	case commands.TreeFormat:
+		g.printAsList(offsets, false)
+	case commands.PlainTextFormat:
+		g.printAsList(offsets, true)
+	}
+
+	if err != nil || g.dryRun {
+		return err
+	}
+
+	if g.silent || commands.AskForConfirmation(fmt.Sprintf("Are you sure you want to reset the offsets of %s group", g.group)) {
+		err := manager.ResetGroupOffsets(ctx, g.group, offsets)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The offsets of %s group have been reset successfully.\n", g.group)
+	}
+	return nil
+}
+
+// getCheckpoints returns the checkpoints to reset the offsets to.
+//
+// The method returns nil if the offsets must be shifted by --shift-by messages.
+func (g *groupOffsets) getCheckpoints() (*kafka.PartitionCheckpoints, error) {
+	var (
+		requested int
+		targets   []string
+	)
+	if g.toEarliest {
+		requested++
+		targets = []string{"earliest"}
+	}
+	if g.toLatest {
+		requested++
+		targets = []string{"latest"}
+	}
+	if !internal.IsEmpty(g.toDatetime) {
+		requested++
+		if _, err := dateparse.ParseAny(g.toDatetime); err != nil {
+			return nil, fmt.Errorf("invalid --to-datetime value: %w", err)
+		}
+		targets = []string{g.toDatetime}
+	}
+	if g.shiftBy != 0 {
+		requested++
+	}
+	if len(g.toOffset) > 0 {
+		requested++
+		targets = g.toOffset
+	}
+
+	if requested != 1 {
+		return nil, errors.New("exactly one of --to-earliest, --to-latest, --to-datetime, --shift-by or --to-offset must be specified")
+	}
+
+	if g.shiftBy != 0 {
+		return nil, nil
+	}
+
+	return kafka.NewTargetCheckpoints(targets)
+}
+
+func (g *groupOffsets) printAsTable(offsets kafka.GroupOffsetsReset) {
+	for _, topic := range offsets.SortedTopics() {
+		table := tabular.NewTable(g.globalParams.EnableColor,
+			tabular.C("Partition").MinWidth(10),
+			tabular.C("Earliest").MinWidth(10).Align(tabular.AlignCenter),
+			tabular.C("Latest").MinWidth(10).Align(tabular.AlignCenter),
+			tabular.C("Current").MinWidth(10).Align(tabular.AlignCenter),
+			tabular.C("New").MinWidth(10).Align(tabular.AlignCenter),
+		)
+		table.SetTitle(fmt.Sprintf("Topic: %s", topic))
+		for _, partition := range offsets[topic] {
+			table.AddRow(
+				strconv.FormatInt(int64(partition.Partition), 10),
+				humanize.Comma(partition.Earliest),
+				humanize.Comma(partition.Latest),
+				formatCurrentOffset(partition.Current),
+				humanize.Comma(partition.Target),
+			)
+		}
+		table.Render()
+	}
+}
+
+func (g *groupOffsets) printAsList(offsets kafka.GroupOffsetsReset, plain bool) {
+	l := list.New(plain)
+	if !plain {
+		l.AddItem(g.group)
+		l.Indent()
+	}
+	for _, topic := range offsets.SortedTopics() {
+		l.AddItem(topic)
+		l.Indent()
+		for _, partition := range offsets[topic] {
+			l.AddItemF("P%d", partition.Partition)
+			l.Indent()
+			l.AddItemF("Earliest: %s", humanize.Comma(partition.Earliest))
+			l.AddItemF("  Latest: %s", humanize.Comma(partition.Latest))
+			l.AddItemF(" Current: %s", formatCurrentOffset(partition.Current))
+			l.AddItemF("     New: %s", humanize.Comma(partition.Target))
+			l.UnIndent()
+		}
+		l.UnIndent()
+	}
+	l.UnIndent()
+	l.Render()
+}
+
+func formatCurrentOffset(offset int64) string {
+	if offset < 0 {
+		return "-"
+	}
+	return humanize.Comma(offset)
+}
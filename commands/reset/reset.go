@@ -0,0 +1,13 @@
+package reset
+
+import (
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+)
+
+// AddCommands adds the reset command to the app.
+func AddCommands(app *kingpin.Application, global *commands.GlobalParameters, kafkaParams *commands.KafkaParameters) {
+	parent := app.Command("reset", "A command to reset Kafka entities.")
+	addResetGroupOffsetsSubCommand(parent, global, kafkaParams)
+}
@@ -0,0 +1,250 @@
+// Package config manages the named cluster profiles, stored in the Trubka configuration file.
+//
+// Each profile holds the connection settings of a Kafka cluster along with the default values of the commonly used
+// flags, so that the profile can be selected using --profile instead of repeating the same flags for every command.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kirsle/configdir"
+	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v3"
+)
+
+const fileName = "config.yaml"
+
+// TLS holds the TLS settings of a profile.
+type TLS struct {
+	// CACert the path to CA Cert file.
+	CACert string `yaml:"ca-cert,omitempty" json:"ca_cert,omitempty"`
+	// ClientCert the path to client certification file to enable mutual TLS authentication.
+	ClientCert string `yaml:"client-cert,omitempty" json:"client_cert,omitempty"`
+	// ClientKey the path to client private key file to enable mutual TLS authentication.
+	ClientKey string `yaml:"client-key,omitempty" json:"client_key,omitempty"`
+	// PKCS12 the path to the PKCS#12 bundle containing the client certificate and private key.
+	PKCS12 string `yaml:"pkcs12,omitempty" json:"pkcs12,omitempty"`
+	// KeyPassword the password to decrypt the client private key or the PKCS#12 bundle.
+	KeyPassword string `yaml:"key-password,omitempty" json:"key_password,omitempty"`
+	// VerifySystemRoots verifies the server certificates using the system root certificates.
+	VerifySystemRoots bool `yaml:"verify-system-roots,omitempty" json:"verify_system_roots,omitempty"`
+	// ServerName the expected host name of the server certificates.
+	ServerName string `yaml:"server-name,omitempty" json:"server_name,omitempty"`
+	// MinVersion the minimum accepted TLS version.
+	MinVersion string `yaml:"min-version,omitempty" json:"min_version,omitempty"`
+	// CipherSuites a comma separated list of the enabled cipher suites.
+	CipherSuites string `yaml:"cipher-suites,omitempty" json:"cipher_suites,omitempty"`
+}
+
+// SASL holds the SASL authentication settings of a profile.
+type SASL struct {
+	// Mechanism SASL authentication mechanism.
+	Mechanism string `yaml:"mechanism,omitempty" json:"mechanism,omitempty"`
+	// Username SASL username.
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	// Password SASL password.
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	// PasswordFile the path to the file to read the SASL password from.
+	PasswordFile string `yaml:"password-file,omitempty" json:"password_file,omitempty"`
+	// PasswordCommand the command to run to read the SASL password from its standard output.
+	PasswordCommand string `yaml:"password-cmd,omitempty" json:"password_cmd,omitempty"`
+	// Version SASL handshake version.
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	// OAuthTokenFile the path to the file to read the OAuth bearer token from.
+	OAuthTokenFile string `yaml:"oauth-token-file,omitempty" json:"oauth_token_file,omitempty"`
+	// OAuthTokenURL the OAuth token endpoint to request the bearer tokens from.
+	OAuthTokenURL string `yaml:"oauth-token-url,omitempty" json:"oauth_token_url,omitempty"`
+	// OAuthScopes a comma separated list of the scopes to request from the token endpoint.
+	OAuthScopes string `yaml:"oauth-scopes,omitempty" json:"oauth_scopes,omitempty"`
+	// KerberosConfig the path to the Kerberos configuration file.
+	KerberosConfig string `yaml:"kerberos-config,omitempty" json:"kerberos_config,omitempty"`
+	// KerberosKeyTab the path to the Kerberos keytab file.
+	KerberosKeyTab string `yaml:"kerberos-keytab,omitempty" json:"kerberos_keytab,omitempty"`
+	// KerberosServiceName the Kerberos service name of the brokers.
+	KerberosServiceName string `yaml:"kerberos-service-name,omitempty" json:"kerberos_service_name,omitempty"`
+	// KerberosRealm the Kerberos realm.
+	KerberosRealm string `yaml:"kerberos-realm,omitempty" json:"kerberos_realm,omitempty"`
+}
+
+// Profile represents a named Kafka cluster profile.
+type Profile struct {
+	// Name the name of the profile.
+	Name string `yaml:"-" json:"name"`
+	// Brokers a comma separated list of host:port strings.
+	Brokers string `yaml:"brokers" json:"brokers"`
+	// KafkaVersion the cluster version.
+	KafkaVersion string `yaml:"kafka-version,omitempty" json:"kafka_version,omitempty"`
+	// TLS the TLS settings. TLS will be enabled if set.
+	TLS *TLS `yaml:"tls,omitempty" json:"tls,omitempty"`
+	// SASL the SASL authentication settings.
+	SASL *SASL `yaml:"sasl,omitempty" json:"sasl,omitempty"`
+	// ProtoRoot the default path to the folder where the *.proto files live.
+	ProtoRoot string `yaml:"proto-root,omitempty" json:"proto_root,omitempty"`
+	// Format the default output format of the consume commands.
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+// Config represents the Trubka configuration file.
+type Config struct {
+	// CurrentProfile the profile which will be used if no profile has been explicitly selected.
+	CurrentProfile string `yaml:"current-profile,omitempty"`
+	// Profiles the named cluster profiles.
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+	path     string
+}
+
+// DefaultPath returns the default path of the configuration file.
+func DefaultPath() string {
+	return filepath.Join(configdir.LocalConfig("trubka"), fileName)
+}
+
+// Load loads the configuration file.
+//
+// An empty configuration will be returned if the file does not exist.
+func Load(path string) (*Config, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand the configuration file path: %w", err)
+	}
+	cfg := &Config{
+		Profiles: make(map[string]*Profile),
+		path:     path,
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	for name, profile := range cfg.Profiles {
+		if profile == nil {
+			return nil, fmt.Errorf("invalid configuration file %s: the %q profile is empty", path, name)
+		}
+		profile.Name = name
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration into the file it has been loaded from.
+//
+// The file is only accessible by the current user, as it may contain credentials.
+func (c *Config) Save() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create the configuration directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, content, 0600); err != nil {
+		return fmt.Errorf("failed to write the configuration file: %w", err)
+	}
+	return nil
+}
+
+// Path returns the path of the configuration file.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Profile returns the profile with the specified name.
+//
+// The current profile will be returned if the name is empty. The method returns nil if the name is empty and
+// no current profile has been set.
+func (c *Config) Profile(name string) (*Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("the %q profile does not exist", name)
+	}
+	return profile, nil
+}
+
+// Names returns the sorted list of the profile names.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Add adds a new profile to the configuration.
+//
+// The existing profile with the same name will only be replaced if overwrite is true.
+func (c *Config) Add(profile *Profile, overwrite bool) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return errors.New("the profile name cannot be empty")
+	}
+	if strings.TrimSpace(profile.Brokers) == "" {
+		return errors.New("the list of brokers cannot be empty")
+	}
+	if sasl := profile.SASL; sasl != nil {
+		var sources int
+		for _, source := range []string{sasl.Password, sasl.PasswordFile, sasl.PasswordCommand} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources > 1 {
+			return errors.New("only one of the SASL password, password file and password command can be set")
+		}
+		if sasl.OAuthTokenFile != "" && sasl.OAuthTokenURL != "" {
+			return errors.New("only one of the OAuth token file and token endpoint can be set")
+		}
+	}
+	if profile.TLS != nil && profile.TLS.ClientCert != "" && profile.TLS.ClientKey == "" {
+		return errors.New("the client key is required to enable TLS mutual authentication")
+	}
+	if profile.TLS != nil && profile.TLS.PKCS12 != "" && profile.TLS.ClientCert != "" {
+		return errors.New("only one of the client certificate and the PKCS#12 bundle can be set")
+	}
+	if _, ok := c.Profiles[profile.Name]; ok && !overwrite {
+		return fmt.Errorf("the %q profile already exists", profile.Name)
+	}
+	c.Profiles[profile.Name] = profile
+	return nil
+}
+
+// Remove removes the profile from the configuration.
+//
+// The current profile will be unset if it is being removed.
+func (c *Config) Remove(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("the %q profile does not exist", name)
+	}
+	delete(c.Profiles, name)
+	if c.CurrentProfile == name {
+		c.CurrentProfile = ""
+	}
+	return nil
+}
+
+// Use sets the current profile.
+func (c *Config) Use(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("the %q profile does not exist", name)
+	}
+	c.CurrentProfile = name
+	return nil
+}
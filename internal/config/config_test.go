@@ -0,0 +1,322 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		title           string
+		content         string
+		expectedError   string
+		expectedCurrent string
+		expectedNames   []string
+	}{
+		{
+			title:         "missing file",
+			expectedNames: []string{},
+		},
+		{
+			title: "valid file",
+			content: `current-profile: prod
+profiles:
+  prod:
+    brokers: prod-1:9092,prod-2:9092
+    tls:
+      ca-cert: /certs/ca.pem
+  dev:
+    brokers: localhost:9092
+`,
+			expectedCurrent: "prod",
+			expectedNames:   []string{"dev", "prod"},
+		},
+		{
+			title:         "invalid yaml",
+			content:       "profiles: [",
+			expectedError: "invalid configuration file",
+		},
+		{
+			title: "empty profile",
+			content: `profiles:
+  prod:
+`,
+			expectedError: `the "prod" profile is empty`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), fileName)
+			if tC.content != "" {
+				if err := os.WriteFile(path, []byte(tC.content), 0600); err != nil {
+					t.Fatalf("Failed to write the configuration file: %s", err)
+				}
+			}
+			cfg, err := Load(path)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.CurrentProfile != tC.expectedCurrent {
+				t.Errorf("Expected current profile: %q, Actual: %q", tC.expectedCurrent, cfg.CurrentProfile)
+			}
+			names := cfg.Names()
+			if strings.Join(names, ",") != strings.Join(tC.expectedNames, ",") {
+				t.Errorf("Expected profiles: %v, Actual: %v", tC.expectedNames, names)
+			}
+			for name, profile := range cfg.Profiles {
+				if profile.Name != name {
+					t.Errorf("Expected profile name: %q, Actual: %q", name, profile.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", fileName)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load the configuration: %s", err)
+	}
+	err = cfg.Add(&Profile{
+		Name:    "prod",
+		Brokers: "prod-1:9092",
+		SASL: &SASL{
+			Mechanism: "plain",
+			Username:  "user",
+			Password:  "secret",
+		},
+		ProtoRoot: "~/protos",
+		Format:    "json-indent",
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to add the profile: %s", err)
+	}
+	if err := cfg.Use("prod"); err != nil {
+		t.Fatalf("Failed to set the current profile: %s", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save the configuration: %s", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat the configuration file: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected file permissions: 0600, Actual: %o", perm)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to reload the configuration: %s", err)
+	}
+	profile, err := loaded.Profile("")
+	if err != nil {
+		t.Fatalf("Failed to load the current profile: %s", err)
+	}
+	if profile == nil {
+		t.Fatal("Expected the current profile to be loaded")
+	}
+	if profile.Name != "prod" || profile.Brokers != "prod-1:9092" || profile.Format != "json-indent" || profile.ProtoRoot != "~/protos" {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+	if profile.SASL == nil || profile.SASL.Password != "secret" {
+		t.Errorf("Unexpected SASL settings: %+v", profile.SASL)
+	}
+	if profile.TLS != nil {
+		t.Errorf("Expected TLS to be disabled, Actual: %+v", profile.TLS)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	testCases := []struct {
+		title         string
+		current       string
+		name          string
+		expected      string
+		expectedError string
+	}{
+		{
+			title:    "named profile",
+			name:     "dev",
+			expected: "dev",
+		},
+		{
+			title:    "named profile overrides the current profile",
+			current:  "prod",
+			name:     "dev",
+			expected: "dev",
+		},
+		{
+			title:    "current profile",
+			current:  "prod",
+			expected: "prod",
+		},
+		{
+			title: "no current profile",
+		},
+		{
+			title:         "missing profile",
+			name:          "staging",
+			expectedError: `the "staging" profile does not exist`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			cfg := &Config{
+				CurrentProfile: tC.current,
+				Profiles: map[string]*Profile{
+					"dev":  {Name: "dev", Brokers: "localhost:9092"},
+					"prod": {Name: "prod", Brokers: "prod-1:9092"},
+				},
+			}
+			profile, err := cfg.Profile(tC.name)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			var actual string
+			if profile != nil {
+				actual = profile.Name
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected profile: %q, Actual: %q", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	testCases := []struct {
+		title         string
+		profile       *Profile
+		overwrite     bool
+		expectedError string
+	}{
+		{
+			title:   "new profile",
+			profile: &Profile{Name: " staging ", Brokers: "staging:9092"},
+		},
+		{
+			title:         "empty name",
+			profile:       &Profile{Name: " ", Brokers: "staging:9092"},
+			expectedError: "the profile name cannot be empty",
+		},
+		{
+			title:         "no brokers",
+			profile:       &Profile{Name: "staging"},
+			expectedError: "the list of brokers cannot be empty",
+		},
+		{
+			title: "client cert without key",
+			profile: &Profile{
+				Name:    "staging",
+				Brokers: "staging:9092",
+				TLS:     &TLS{ClientCert: "cert.pem"},
+			},
+			expectedError: "the client key is required",
+		},
+		{
+			title: "client certificate with pkcs12 bundle",
+			profile: &Profile{
+				Name:    "staging",
+				Brokers: "staging:9092",
+				TLS:     &TLS{ClientCert: "cert.pem", ClientKey: "key.pem", PKCS12: "client.p12"},
+			},
+			expectedError: "only one of the client certificate and the PKCS#12 bundle can be set",
+		},
+		{
+			title: "multiple password sources",
+			profile: &Profile{
+				Name:    "staging",
+				Brokers: "staging:9092",
+				SASL:    &SASL{Password: "secret", PasswordCommand: "pass show kafka"},
+			},
+			expectedError: "only one of the SASL password, password file and password command can be set",
+		},
+		{
+			title: "multiple oauth token sources",
+			profile: &Profile{
+				Name:    "staging",
+				Brokers: "staging:9092",
+				SASL:    &SASL{Mechanism: "oauthbearer", OAuthTokenFile: "token", OAuthTokenURL: "http://localhost/token"},
+			},
+			expectedError: "only one of the OAuth token file and token endpoint can be set",
+		},
+		{
+			title:         "existing profile",
+			profile:       &Profile{Name: "dev", Brokers: "dev:9092"},
+			expectedError: `the "dev" profile already exists`,
+		},
+		{
+			title:     "overwrite existing profile",
+			profile:   &Profile{Name: "dev", Brokers: "dev:9092"},
+			overwrite: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			cfg := &Config{
+				Profiles: map[string]*Profile{
+					"dev": {Name: "dev", Brokers: "localhost:9092"},
+				},
+			}
+			err := cfg.Add(tC.profile, tC.overwrite)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Profiles[tC.profile.Name] != tC.profile {
+				t.Errorf("Expected the %q profile to be added", tC.profile.Name)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	cfg := &Config{
+		CurrentProfile: "prod",
+		Profiles: map[string]*Profile{
+			"dev":  {Name: "dev", Brokers: "localhost:9092"},
+			"prod": {Name: "prod", Brokers: "prod-1:9092"},
+		},
+	}
+	if err := cfg.Remove("dev"); err != nil {
+		t.Fatalf("Failed to remove the profile: %s", err)
+	}
+	if cfg.CurrentProfile != "prod" {
+		t.Errorf("Expected the current profile to remain prod, Actual: %q", cfg.CurrentProfile)
+	}
+	if err := cfg.Remove("prod"); err != nil {
+		t.Fatalf("Failed to remove the profile: %s", err)
+	}
+	if cfg.CurrentProfile != "" {
+		t.Errorf("Expected the current profile to be unset, Actual: %q", cfg.CurrentProfile)
+	}
+	if err := cfg.Remove("prod"); !checkError(err, `the "prod" profile does not exist`) {
+		t.Errorf("Expected error for removing a missing profile, Actual: %v", err)
+	}
+	if err := cfg.Use("prod"); !checkError(err, `the "prod" profile does not exist`) {
+		t.Errorf("Expected error for using a missing profile, Actual: %v", err)
+	}
+}
+
+func checkError(actual error, expected string) bool {
+	if actual == nil {
+		return expected == ""
+	}
+	if expected == "" {
+		return false
+	}
+	return strings.Contains(actual.Error(), expected)
+}
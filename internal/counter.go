@@ -10,6 +10,7 @@ import (
 type stats struct {
 	success int64
 	failure int64
+	skipped int64
 }
 
 // Counter represents a in-memory counter for consumed Kafka events.
@@ -29,10 +30,19 @@ func (c *Counter) PrintAsTable(highlight bool) {
 	if c == nil || len(c.topicStats) == 0 {
 		return
 	}
-	table := tabular.NewTable(highlight,
+	var hasSkipped bool
+	for _, s := range c.topicStats {
+		hasSkipped = hasSkipped || s.skipped > 0
+	}
+	columns := []*tabular.Column{
 		tabular.C("Topic").Align(tabular.AlignLeft),
 		tabular.C("Succeeded"),
-		tabular.C("Failed"))
+		tabular.C("Failed"),
+	}
+	if hasSkipped {
+		columns = append(columns, tabular.C("Skipped"))
+	}
+	table := tabular.NewTable(highlight, columns...)
 
 	for topic, s := range c.topicStats {
 		failed := format.RedIfTrue(humanize.Comma(s.failure), func() bool {
@@ -42,8 +52,15 @@ func (c *Counter) PrintAsTable(highlight bool) {
 		succeeded := format.GreenIfTrue(humanize.Comma(s.success), func() bool {
 			return s.success > 0
 		}, highlight)
+		if hasSkipped {
+			table.AddRow(topic, succeeded, failed, humanize.Comma(s.skipped))
+			continue
+		}
 		table.AddRow(topic, succeeded, failed)
 	}
+	if hasSkipped {
+		table.SetCaption("Skipped: the offsets which have not been delivered to the consumer (eg. transaction markers, aborted transactions or compacted records).")
+	}
 	table.SetTitle("SUMMARY")
 	table.TitleAlignment(tabular.AlignCenter)
 	table.Render()
@@ -64,3 +81,14 @@ func (c *Counter) IncrFailure(topic string) {
 	}
 	c.topicStats[topic].failure++
 }
+
+// IncrSkipped increases the number of the skipped offsets.
+func (c *Counter) IncrSkipped(topic string, skipped int64) {
+	if skipped <= 0 {
+		return
+	}
+	if _, ok := c.topicStats[topic]; !ok {
+		c.topicStats[topic] = &stats{}
+	}
+	c.topicStats[topic].skipped += skipped
+}
@@ -0,0 +1,255 @@
+package filter
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+type condition interface {
+	match(document interface{}) bool
+}
+
+type operand interface {
+	// resolve returns the value of the operand, and false if the operand does not exist in the document.
+	resolve(document interface{}) (interface{}, bool)
+}
+
+type and struct {
+	left, right condition
+}
+
+func (a *and) match(document interface{}) bool {
+	return a.left.match(document) && a.right.match(document)
+}
+
+type or struct {
+	left, right condition
+}
+
+func (o *or) match(document interface{}) bool {
+	return o.left.match(document) || o.right.match(document)
+}
+
+type not struct {
+	operand condition
+}
+
+func (n *not) match(document interface{}) bool {
+	return !n.operand.match(document)
+}
+
+// exists is true if the operand exists and is neither null nor false.
+type exists struct {
+	operand operand
+}
+
+func (e *exists) match(document interface{}) bool {
+	v, ok := e.operand.resolve(document)
+	if !ok || v == nil {
+		return false
+	}
+	if b, isBool := v.(bool); isBool {
+		return b
+	}
+	return true
+}
+
+type comparison struct {
+	op          string
+	left, right operand
+	regex       *regexp.Regexp
+}
+
+func (c *comparison) match(document interface{}) bool {
+	// The fields which do not exist in the document are treated as null.
+	left, _ := c.left.resolve(document)
+	right, _ := c.right.resolve(document)
+	switch c.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		items, _ := right.(list)
+		for _, item := range items {
+			if equal(left, item) {
+				return true
+			}
+		}
+		return false
+	case "=~":
+		s, ok := left.(string)
+		return ok && c.regex.MatchString(s)
+	}
+	result, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	default:
+		return false
+	}
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) resolve(_ interface{}) (interface{}, bool) {
+	return l.value, true
+}
+
+type list []interface{}
+
+func (l list) resolve(_ interface{}) (interface{}, bool) {
+	return l, true
+}
+
+// path the field names (string) and the array indices (int) to reach a field of the document.
+type path []interface{}
+
+func (p path) resolve(document interface{}) (interface{}, bool) {
+	current := document
+	for _, segment := range p {
+		switch s := segment.(type) {
+		case string:
+			fields, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			current, ok = fields[s]
+			if !ok {
+				return nil, false
+			}
+		case int:
+			items, ok := current.([]interface{})
+			if !ok || s >= len(items) {
+				return nil, false
+			}
+			current = items[s]
+		}
+	}
+	return current, true
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if result, ok := compareNumbers(a, b); ok {
+		return result == 0
+	}
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+	default:
+		return false
+	}
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater than b. The second return value will be
+// false if the values are not both numbers or both strings.
+func compare(a, b interface{}) (int, bool) {
+	if result, ok := compareNumbers(a, b); ok {
+		return result, true
+	}
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// number holds the integer values separately to compare the large integers without losing precision.
+type number struct {
+	integer int64
+	float   float64
+	isInt   bool
+}
+
+func compareNumbers(a, b interface{}) (int, bool) {
+	x, ok := toNumber(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := toNumber(b)
+	if !ok {
+		return 0, false
+	}
+	if x.isInt && y.isInt {
+		switch {
+		case x.integer < y.integer:
+			return -1, true
+		case x.integer > y.integer:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	switch {
+	case x.float < y.float:
+		return -1, true
+	case x.float > y.float:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+func toNumber(v interface{}) (number, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return number{integer: i, float: float64(i), isInt: true}, true
+		}
+		f, err := strconv.ParseFloat(string(n), 64)
+		if err != nil {
+			return number{}, false
+		}
+		return number{float: f}, true
+	case int:
+		return number{integer: int64(n), float: float64(n), isInt: true}, true
+	case int32:
+		return number{integer: int64(n), float: float64(n), isInt: true}, true
+	case int64:
+		return number{integer: n, float: float64(n), isInt: true}, true
+	case uint32:
+		return number{integer: int64(n), float: float64(n), isInt: true}, true
+	case uint64:
+		if n > math.MaxInt64 {
+			return number{float: float64(n)}, true
+		}
+		return number{integer: int64(n), float: float64(n), isInt: true}, true
+	case float32:
+		return number{float: float64(n)}, true
+	case float64:
+		return number{float: n}, true
+	default:
+		return number{}, false
+	}
+}
@@ -0,0 +1,75 @@
+// Package filter implements a small expression language to filter the decoded messages by their fields, along with
+// the selectors to extract or reshape the fields of the messages (see Selector).
+//
+// The expressions compare the fields of the message with literal values, for example:
+//
+//	user.id == 42 && status in ["FAILED", "RETRY"]
+//	!(items[0].price < 10.5) || name =~ "^trubka"
+//
+// The supported operators are ==, !=, <, <=, >, >=, in (list membership) and =~ (regular expression match),
+// which can be combined using &&, || and !. A field on its own is true if it exists and is neither null nor false.
+// The nested fields are accessed using dots, the array elements using [index] and the nested fields with special
+// characters in their names using ["field name"] (eg. meta["content-type"]).
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Expression represents a compiled filter expression.
+type Expression struct {
+	source string
+	root   condition
+}
+
+// Parse compiles the filter expression.
+func Parse(input string) (*Expression, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("the filter expression cannot be empty")
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter expression: %w", err)
+	}
+	if t := p.peek(); t.kind != endToken {
+		return nil, fmt.Errorf("invalid filter expression: unexpected %s", t)
+	}
+	return &Expression{
+		source: input,
+		root:   root,
+	}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Match evaluates the expression against the decoded document.
+//
+// The document is expected to be made of maps with string keys, slices and scalar values, like the output of
+// json.Unmarshal into an empty interface.
+func (e *Expression) Match(document interface{}) bool {
+	return e.root.match(document)
+}
+
+// MatchJSON decodes the Json content and evaluates the expression against it.
+func (e *Expression) MatchJSON(content []byte) (bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return false, fmt.Errorf("the message is not a valid Json document: %w", err)
+	}
+	return e.Match(document), nil
+}
@@ -0,0 +1,272 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+const document = `{
+  "user": {"id": 42, "name": "trubka", "active": true, "score": 9.5},
+  "status": "FAILED",
+  "id": 9007199254740993,
+  "tags": ["kafka", "cli"],
+  "items": [{"price": 10.5}, {"price": 3}],
+  "deleted_at": null,
+  "meta": {"content type": "x", "content-type": "json"}
+}`
+
+func TestMatchJSON(t *testing.T) {
+	testCases := []struct {
+		title      string
+		expression string
+		expected   bool
+	}{
+		{
+			title:      "nested number equality",
+			expression: `user.id == 42`,
+			expected:   true,
+		},
+		{
+			title:      "number equality with float literal",
+			expression: `user.id == 42.0`,
+			expected:   true,
+		},
+		{
+			title:      "string inequality",
+			expression: `status != "FAILED"`,
+			expected:   false,
+		},
+		{
+			title:      "membership",
+			expression: `user.id == 42 && status in ["FAILED", 'RETRY']`,
+			expected:   true,
+		},
+		{
+			title:      "no membership",
+			expression: `status in ["OK"]`,
+			expected:   false,
+		},
+		{
+			title:      "large integers",
+			expression: `id == 9007199254740993 && id != 9007199254740992`,
+			expected:   true,
+		},
+		{
+			title:      "number comparison",
+			expression: `user.score >= 9.5 && user.score < 10 && user.id > -1`,
+			expected:   true,
+		},
+		{
+			title:      "string comparison",
+			expression: `user.name > "a"`,
+			expected:   true,
+		},
+		{
+			title:      "comparing different types",
+			expression: `user.name > 1 || user.id == "42"`,
+			expected:   false,
+		},
+		{
+			title:      "array index",
+			expression: `items[0].price == 10.5 && items[1].price == 3 && tags[1] == "cli"`,
+			expected:   true,
+		},
+		{
+			title:      "array index out of range",
+			expression: `items[5].price == 10.5`,
+			expected:   false,
+		},
+		{
+			title:      "quoted field name",
+			expression: `meta["content type"] == "x"`,
+			expected:   true,
+		},
+		{
+			title:      "quoted hyphenated field name",
+			expression: `meta["content-type"] == "json"`,
+			expected:   true,
+		},
+		{
+			title:      "regular expression",
+			expression: `user.name =~ "^tru"`,
+			expected:   true,
+		},
+		{
+			title:      "regular expression on non-string field",
+			expression: `user.id =~ "42"`,
+			expected:   false,
+		},
+		{
+			title:      "existing field",
+			expression: `user.active && user.name`,
+			expected:   true,
+		},
+		{
+			title:      "null field",
+			expression: `deleted_at`,
+			expected:   false,
+		},
+		{
+			title:      "missing field",
+			expression: `user.email`,
+			expected:   false,
+		},
+		{
+			title:      "missing field equals null",
+			expression: `user.email == null && deleted_at == null`,
+			expected:   true,
+		},
+		{
+			title:      "negation",
+			expression: `!(status == "FAILED") || !user.active`,
+			expected:   false,
+		},
+		{
+			title:      "precedence",
+			expression: `status == "OK" && user.id == 1 || user.id == 42`,
+			expected:   true,
+		},
+		{
+			title:      "grouping",
+			expression: `status == "OK" && (user.id == 1 || user.id == 42)`,
+			expected:   false,
+		},
+		{
+			title:      "boolean literal",
+			expression: `user.active == true && user.active != false`,
+			expected:   true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			expression, err := Parse(tC.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse the expression: %s", err)
+			}
+			actual, err := expression.MatchJSON([]byte(document))
+			if err != nil {
+				t.Fatalf("Failed to evaluate the expression: %s", err)
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected: %v, Actual: %v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	doc := map[string]interface{}{
+		"id":     int64(42),
+		"count":  uint64(7),
+		"ratio":  float32(0.5),
+		"status": "FAILED",
+		"nested": map[string]interface{}{
+			"values": []interface{}{int32(1), int32(2)},
+		},
+	}
+	expression, err := Parse(`id == 42 && count < 8 && ratio == 0.5 && nested.values[1] in [2, 3]`)
+	if err != nil {
+		t.Fatalf("Failed to parse the expression: %s", err)
+	}
+	if !expression.Match(doc) {
+		t.Errorf("Expected the document to match %s", expression)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		title         string
+		expression    string
+		expectedError string
+	}{
+		{
+			title:         "empty expression",
+			expression:    "  ",
+			expectedError: "the filter expression cannot be empty",
+		},
+		{
+			title:         "unexpected character",
+			expression:    `user.id # 2`,
+			expectedError: "unexpected character '#' at position 9",
+		},
+		{
+			title:         "unterminated string",
+			expression:    `status == "FAILED`,
+			expectedError: "unterminated string at position 11",
+		},
+		{
+			title:         "missing right operand",
+			expression:    `status ==`,
+			expectedError: "expected a field, a value or a list, found end of expression",
+		},
+		{
+			title:         "missing closing parenthesis",
+			expression:    `(status == "A"`,
+			expectedError: `expected ")", found end of expression`,
+		},
+		{
+			title:         "in without list",
+			expression:    `status in "A"`,
+			expectedError: `the right operand of 'in' must be a list (eg. [1, 2]), found "\"A\"" at position 11`,
+		},
+		{
+			title:         "fields in list",
+			expression:    `status in [a, b]`,
+			expectedError: "the lists can only contain strings, numbers, booleans or null",
+		},
+		{
+			title:         "invalid regular expression",
+			expression:    `status =~ "("`,
+			expectedError: `invalid regular expression "("`,
+		},
+		{
+			title:         "regular expression without string",
+			expression:    `status =~ 12`,
+			expectedError: `the right operand of '=~' must be a regular expression string`,
+		},
+		{
+			title:         "trailing tokens",
+			expression:    `status == "A" status`,
+			expectedError: `unexpected "status" at position 15`,
+		},
+		{
+			title:         "invalid number",
+			expression:    `id == 1.2.3`,
+			expectedError: `invalid number "1.2.3" at position 7`,
+		},
+		{
+			title:         "hyphenated field name",
+			expression:    `meta.content-type == "json"`,
+			expectedError: `unexpected character '-' at position 13`,
+		},
+		{
+			title:         "subtraction",
+			expression:    `a-1 == 2`,
+			expectedError: `unexpected "-1" at position 2`,
+		},
+		{
+			title:         "invalid index",
+			expression:    `items[-1] == 1`,
+			expectedError: `invalid index "-1" at position 7`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			_, err := Parse(tC.expression)
+			if err == nil || !strings.Contains(err.Error(), tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %v", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMatchInvalidJSON(t *testing.T) {
+	expression, err := Parse(`id == 1`)
+	if err != nil {
+		t.Fatalf("Failed to parse the expression: %s", err)
+	}
+	_, err = expression.MatchJSON([]byte("plain text"))
+	if err == nil {
+		t.Error("Expected an error for invalid Json content")
+	}
+}
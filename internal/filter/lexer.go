@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identToken
+	stringToken
+	numberToken
+	symbolToken
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+func (t token) is(symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+// symbols the operators and punctuations of the expression language. The longer symbols must come first.
+var symbols = []string{"==", "!=", "<=", ">=", "=~", "&&", "||", "<", ">", "!", "(", ")", "[", "]", "{", "}", ",", ".", ":"}
+
+func tokenize(input string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			value, end, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: stringToken, text: string(runes[i:end]), value: value, pos: i})
+			i = end
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && isNumberRune(runes[end], runes[end-1]) {
+				end++
+			}
+			text := string(runes[i:end])
+			tokens = append(tokens, token{kind: numberToken, text: text, value: text, pos: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && isIdentRune(runes[end]) {
+				end++
+			}
+			text := string(runes[i:end])
+			tokens = append(tokens, token{kind: identToken, text: text, value: text, pos: i})
+			i = end
+		default:
+			var matched bool
+			for _, symbol := range symbols {
+				if strings.HasPrefix(string(runes[i:]), symbol) {
+					tokens = append(tokens, token{kind: symbolToken, text: symbol, value: symbol, pos: i})
+					i += len([]rune(symbol))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+			}
+		}
+	}
+	return append(tokens, token{kind: endToken, pos: len(runes)}), nil
+}
+
+func readString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var (
+		sb      strings.Builder
+		escaped bool
+	)
+	for i := start + 1; i < len(runes); i++ {
+		r := runes[i]
+		if escaped {
+			switch r {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			default:
+				sb.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string at position %d", start+1)
+}
+
+func isNumberRune(r, previous rune) bool {
+	if unicode.IsDigit(r) || r == '.' || r == 'e' || r == 'E' {
+		return true
+	}
+	return (r == '-' || r == '+') && (previous == 'e' || previous == 'E')
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != endToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(symbol string) error {
+	t := p.next()
+	if !t.is(symbol) {
+		return fmt.Errorf("expected %q, found %s", symbol, t)
+	}
+	return nil
+}
+
+// parseOr parses: and ('||' and)*
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &or{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: not ('&&' not)*
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("&&") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &and{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses: '!' not | primary
+func (p *parser) parseNot() (condition, error) {
+	if p.peek().is("!") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &not{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: '(' or ')' | operand (operator operand)?
+func (p *parser) parsePrimary() (condition, error) {
+	if p.peek().is("(") {
+		p.next()
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	var op string
+	switch {
+	case t.kind == symbolToken && isComparisonOperator(t.text):
+		op = t.text
+	case t.kind == identToken && t.text == "in":
+		op = t.text
+	default:
+		return &exists{operand: left}, nil
+	}
+	p.next()
+
+	rightToken := p.peek()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	c := &comparison{
+		op:    op,
+		left:  left,
+		right: right,
+	}
+	switch op {
+	case "in":
+		if _, ok := right.(list); !ok {
+			return nil, fmt.Errorf("the right operand of 'in' must be a list (eg. [1, 2]), found %s", rightToken)
+		}
+	case "=~":
+		l, ok := right.(literal)
+		s, isString := l.value.(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("the right operand of '=~' must be a regular expression string, found %s", rightToken)
+		}
+		c.regex, err = regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", s, err)
+		}
+	}
+	return c, nil
+}
+
+// parseOperand parses: literal | path | list
+func (p *parser) parseOperand() (operand, error) {
+	t := p.next()
+	switch t.kind {
+	case stringToken:
+		return literal{value: t.value}, nil
+	case numberToken:
+		return parseNumber(t)
+	case identToken:
+		switch t.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+		return p.parsePath(t)
+	case symbolToken:
+		if t.is("[") {
+			return p.parseList()
+		}
+	}
+	return nil, fmt.Errorf("expected a field, a value or a list, found %s", t)
+}
+
+// parsePath parses: ident ('.' ident | '[' (number | string) ']')*
+func (p *parser) parsePath(first token) (operand, error) {
+	result := path{first.text}
+	for {
+		switch {
+		case p.peek().is("."):
+			p.next()
+			t := p.next()
+			if t.kind != identToken {
+				return nil, fmt.Errorf("expected a field name, found %s", t)
+			}
+			result = append(result, t.text)
+		case p.peek().is("["):
+			p.next()
+			t := p.next()
+			switch t.kind {
+			case stringToken:
+				result = append(result, t.value)
+			case numberToken:
+				index, err := parseIndex(t)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, index)
+			default:
+				return nil, fmt.Errorf("expected an index or a quoted field name, found %s", t)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return result, nil
+		}
+	}
+}
+
+// parseList parses: '[' (literal (',' literal)*)? ']'
+func (p *parser) parseList() (operand, error) {
+	result := make(list, 0)
+	if p.peek().is("]") {
+		p.next()
+		return result, nil
+	}
+	for {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		l, ok := item.(literal)
+		if !ok {
+			return nil, errors.New("the lists can only contain strings, numbers, booleans or null")
+		}
+		result = append(result, l.value)
+		t := p.next()
+		if t.is("]") {
+			return result, nil
+		}
+		if !t.is(",") {
+			return nil, fmt.Errorf("expected ',' or ']', found %s", t)
+		}
+	}
+}
+
+func parseNumber(t token) (operand, error) {
+	if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+		return nil, fmt.Errorf("invalid number %s", t)
+	}
+	return literal{value: json.Number(t.text)}, nil
+}
+
+func parseIndex(t token) (int, error) {
+	index, err := strconv.Atoi(t.text)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid index %s", t)
+	}
+	return index, nil
+}
+
+func isComparisonOperator(symbol string) bool {
+	switch symbol {
+	case "==", "!=", "<", "<=", ">", ">=", "=~":
+		return true
+	default:
+		return false
+	}
+}
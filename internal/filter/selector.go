@@ -0,0 +1,257 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Selector represents a compiled field selector which extracts or reshapes the fields of the decoded messages.
+//
+// The selectors follow a subset of the jq syntax, for example:
+//
+//	.user.name
+//	.items[].price
+//	{id: .user.id, status, first_tag: .tags[0]}
+//
+// A single dot selects the entire document. The [] operator selects the field from every element of an array.
+// A field name on its own within the braces is a shorthand for name: .name. The missing fields are selected as null.
+type Selector struct {
+	source string
+	root   projection
+}
+
+// ParseSelector compiles the field selector.
+func ParseSelector(input string) (*Selector, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("the selector cannot be empty")
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseProjection()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	if t := p.peek(); t.kind != endToken {
+		return nil, fmt.Errorf("invalid selector: unexpected %s", t)
+	}
+	return &Selector{
+		source: input,
+		root:   root,
+	}, nil
+}
+
+// String returns the source of the selector.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// Select applies the selector to the decoded document.
+func (s *Selector) Select(document interface{}) interface{} {
+	return s.root.project(document)
+}
+
+// SelectJSON decodes the Json content, applies the selector and returns the Json representation of the result.
+//
+// The result will be indented if indent is not empty.
+func (s *Selector) SelectJSON(content []byte, indent string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("the message is not a valid Json document: %w", err)
+	}
+	result := s.Select(document)
+	if indent != "" {
+		return json.MarshalIndent(result, "", indent)
+	}
+	return json.Marshal(result)
+}
+
+type projection interface {
+	project(document interface{}) interface{}
+}
+
+// iterate the path segment which selects the rest of the path from every element of an array.
+type iterate struct{}
+
+type pathProjection []interface{}
+
+func (p pathProjection) project(document interface{}) interface{} {
+	current := document
+	for i, segment := range p {
+		switch s := segment.(type) {
+		case string:
+			fields, ok := current.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			current = fields[s]
+		case int:
+			items, ok := current.([]interface{})
+			if !ok || s >= len(items) {
+				return nil
+			}
+			current = items[s]
+		case iterate:
+			items, ok := current.([]interface{})
+			if !ok {
+				return nil
+			}
+			rest := p[i+1:]
+			result := make([]interface{}, len(items))
+			for j, item := range items {
+				result[j] = rest.project(item)
+			}
+			return result
+		}
+	}
+	return current
+}
+
+type objectField struct {
+	name  string
+	value projection
+}
+
+// objectProjection builds a new object, keeping the fields in the same order as they appear in the selector.
+type objectProjection []objectField
+
+func (o objectProjection) project(document interface{}) interface{} {
+	result := make(orderedObject, len(o))
+	for i, field := range o {
+		result[i] = orderedField{
+			name:  field.name,
+			value: field.value.project(document),
+		}
+	}
+	return result
+}
+
+type orderedField struct {
+	name  string
+	value interface{}
+}
+
+type orderedObject []orderedField
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseProjection parses: object | path
+func (p *parser) parseProjection() (projection, error) {
+	if p.peek().is("{") {
+		return p.parseObjectProjection()
+	}
+	return p.parsePathProjection()
+}
+
+// parseObjectProjection parses: '{' field (',' field)* '}' where field is (name ':' projection) | name
+func (p *parser) parseObjectProjection() (projection, error) {
+	p.next()
+	result := make(objectProjection, 0)
+	for {
+		t := p.next()
+		if t.kind != identToken && t.kind != stringToken {
+			return nil, fmt.Errorf("expected a field name, found %s", t)
+		}
+		field := objectField{name: t.value}
+		if p.peek().is(":") {
+			p.next()
+			value, err := p.parseProjection()
+			if err != nil {
+				return nil, err
+			}
+			field.value = value
+		} else {
+			field.value = pathProjection{t.value}
+		}
+		result = append(result, field)
+
+		t = p.next()
+		if t.is("}") {
+			return result, nil
+		}
+		if !t.is(",") {
+			return nil, fmt.Errorf("expected ',' or '}', found %s", t)
+		}
+	}
+}
+
+// parsePathProjection parses: '.'? (name | '.' name | '[' (number | string)? ']')*
+func (p *parser) parsePathProjection() (projection, error) {
+	result := make(pathProjection, 0)
+	start := p.peek()
+	switch {
+	case start.is("."):
+		p.next()
+		if p.peek().kind == identToken {
+			result = append(result, p.next().text)
+		}
+	case start.kind == identToken:
+		result = append(result, p.next().text)
+	case !start.is("["):
+		return nil, fmt.Errorf("expected a field path, found %s", start)
+	}
+	for {
+		switch {
+		case p.peek().is("."):
+			p.next()
+			t := p.next()
+			if t.kind != identToken {
+				return nil, fmt.Errorf("expected a field name, found %s", t)
+			}
+			result = append(result, t.text)
+		case p.peek().is("["):
+			p.next()
+			t := p.next()
+			switch t.kind {
+			case stringToken:
+				result = append(result, t.value)
+			case numberToken:
+				index, err := parseIndex(t)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, index)
+			default:
+				if t.is("]") {
+					result = append(result, iterate{})
+					continue
+				}
+				return nil, fmt.Errorf("expected an index, a quoted field name or ']', found %s", t)
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return result, nil
+		}
+	}
+}
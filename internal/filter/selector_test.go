@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectJSON(t *testing.T) {
+	testCases := []struct {
+		title    string
+		selector string
+		indent   string
+		expected string
+	}{
+		{
+			title:    "entire document",
+			selector: `.`,
+			expected: `{"a":1,"b":[2,3]}`,
+		},
+		{
+			title:    "nested field",
+			selector: `.user.name`,
+			expected: `"trubka"`,
+		},
+		{
+			title:    "nested field without leading dot",
+			selector: `user.id`,
+			expected: `42`,
+		},
+		{
+			title:    "large integers",
+			selector: `.id`,
+			expected: `9007199254740993`,
+		},
+		{
+			title:    "array index",
+			selector: `.tags[1]`,
+			expected: `"cli"`,
+		},
+		{
+			title:    "array index out of range",
+			selector: `.tags[5]`,
+			expected: `null`,
+		},
+		{
+			title:    "array iteration",
+			selector: `.items[].price`,
+			expected: `[10.5,3]`,
+		},
+		{
+			title:    "quoted field name",
+			selector: `.meta["content type"]`,
+			expected: `"x"`,
+		},
+		{
+			title:    "missing field",
+			selector: `.user.email`,
+			expected: `null`,
+		},
+		{
+			title:    "object",
+			selector: `{status, id: .user.id, prices: .items[].price, "first tag": .tags[0]}`,
+			expected: `{"status":"FAILED","id":42,"prices":[10.5,3],"first tag":"kafka"}`,
+		},
+		{
+			title:    "nested object",
+			selector: `{user: {name: .user.name}, missing}`,
+			expected: `{"user":{"name":"trubka"},"missing":null}`,
+		},
+		{
+			title:    "indented output",
+			selector: `{status}`,
+			indent:   "  ",
+			expected: "{\n  \"status\": \"FAILED\"\n}",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			selector, err := ParseSelector(tC.selector)
+			if err != nil {
+				t.Fatalf("Failed to parse the selector: %s", err)
+			}
+			input := document
+			if tC.selector == "." {
+				input = `{"a": 1, "b": [2, 3]}`
+			}
+			actual, err := selector.SelectJSON([]byte(input), tC.indent)
+			if err != nil {
+				t.Fatalf("Failed to apply the selector: %s", err)
+			}
+			if string(actual) != tC.expected {
+				t.Errorf("Expected: %s, Actual: %s", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	testCases := []struct {
+		title         string
+		selector      string
+		expectedError string
+	}{
+		{
+			title:         "empty selector",
+			selector:      "  ",
+			expectedError: "the selector cannot be empty",
+		},
+		{
+			title:         "unexpected character",
+			selector:      `.user#name`,
+			expectedError: "unexpected character '#' at position 6",
+		},
+		{
+			title:         "missing field name",
+			selector:      `.user.`,
+			expectedError: "expected a field name, found end of expression",
+		},
+		{
+			title:         "invalid index",
+			selector:      `.items[-1]`,
+			expectedError: `invalid index "-1" at position 8`,
+		},
+		{
+			title:         "unclosed object",
+			selector:      `{id: .id`,
+			expectedError: `expected ',' or '}', found end of expression`,
+		},
+		{
+			title:         "invalid object field",
+			selector:      `{.id}`,
+			expectedError: `expected a field name, found "." at position 2`,
+		},
+		{
+			title:         "trailing tokens",
+			selector:      `.id name`,
+			expectedError: `unexpected "name" at position 5`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			_, err := ParseSelector(tC.selector)
+			if err == nil || !strings.Contains(err.Error(), tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %v", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSelectInvalidJSON(t *testing.T) {
+	selector, err := ParseSelector(`.id`)
+	if err != nil {
+		t.Fatalf("Failed to parse the selector: %s", err)
+	}
+	_, err = selector.SelectJSON([]byte("plain text"), "")
+	if err == nil {
+		t.Error("Expected an error for invalid Json content")
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/xitonix/trubka/internal/filter"
 )
 
 // JSONIndentation the indentation of JSON output.
@@ -18,6 +22,7 @@ type JSONMessageProcessor struct {
 	highlighter    *JSONHighlighter
 	indent         bool
 	inclusions     *MessageMetadata
+	selector       *filter.Selector
 }
 
 // NewJSONMessageProcessor creates a new instance of JSON message processor.
@@ -25,30 +30,50 @@ func NewJSONMessageProcessor(
 	outputFormat string,
 	inclusions *MessageMetadata,
 	enableColor bool,
-	highlightStyle string) *JSONMessageProcessor {
+	highlightStyle string,
+	selector *filter.Selector) *JSONMessageProcessor {
 	return &JSONMessageProcessor{
 		outputEncoding: outputFormat,
 		inclusions:     inclusions,
 		enableColor:    enableColor,
 		highlighter:    NewJSONHighlighter(highlightStyle, enableColor),
 		indent:         outputFormat == JSONIndentEncoding,
+		selector:       selector,
 	}
 }
 
 // Process prepares json output for printing.
 //
-// The method injects the metadata into the json object if required.
-func (j *JSONMessageProcessor) Process(message, key []byte, ts time.Time, topic string, partition int32, offset int64) ([]byte, error) {
+// The method applies the field selector (if any) before injecting the metadata into the json object if required.
+func (j *JSONMessageProcessor) Process(message, key []byte, ts time.Time, topic string, partition int32, offset int64, headers []*sarama.RecordHeader) ([]byte, error) {
+	if j.selector != nil {
+		var indentation string
+		if j.indent {
+			indentation = JSONIndentation
+		}
+		selected, err := j.selector.SelectJSON(message, indentation)
+		if err != nil {
+			return nil, err
+		}
+		message = selected
+	}
+
 	if !j.inclusions.IsRequested() {
 		return j.highlight(message), nil
 	}
 
+	type header struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+
 	output := struct {
 		Topic        string          `json:"topic,omitempty"`
 		Timestamp    string          `json:"timestamp,omitempty"`
 		Partition    *int32          `json:"partition,omitempty"`
 		PartitionKey string          `json:"key,omitempty"`
 		Offset       *int64          `json:"offset,omitempty"`
+		Headers      []header        `json:"headers,omitempty"`
 		Message      json.RawMessage `json:"message"`
 	}{
 		Message: message,
@@ -73,6 +98,18 @@ func (j *JSONMessageProcessor) Process(message, key []byte, ts time.Time, topic
 	if j.inclusions.Timestamp {
 		output.Timestamp = FormatTime(ts)
 	}
+
+	if j.inclusions.Headers {
+		for _, h := range headers {
+			if h == nil {
+				continue
+			}
+			output.Headers = append(output.Headers, header{
+				Key:   string(h.Key),
+				Value: string(h.Value),
+			})
+		}
+	}
 	var err error
 	if j.indent {
 		message, err = json.MarshalIndent(output, "", JSONIndentation)
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/IBM/sarama"
 )
 
 const (
@@ -13,6 +15,7 @@ const (
 	offsetPrefix    = "Offset"
 	keyPrefix       = "Key"
 	timePrefix      = "Time"
+	headersPrefix   = "Headers"
 )
 
 // MessageMetadata represents the message metadata which should be included in the output.
@@ -27,21 +30,27 @@ type MessageMetadata struct {
 	Timestamp bool
 	// Topic enabled printing topic name to the output.
 	Topic bool
+	// Headers enables printing message headers to the output.
+	Headers bool
 
 	maxPrefixLength int
 }
 
 // IsRequested returns true if any piece of metadata has been requested by the user to be included in the output.
 func (m *MessageMetadata) IsRequested() bool {
-	return m.Timestamp || m.Key || m.Offset || m.Partition || m.Topic
+	return m.Timestamp || m.Key || m.Offset || m.Partition || m.Topic || m.Headers
 }
 
 // Render prepends the requested metadata to the message.
-func (m *MessageMetadata) Render(key, message []byte, ts time.Time, topic string, partition int32, offset int64, b64 bool) []byte {
+func (m *MessageMetadata) Render(key, message []byte, ts time.Time, topic string, partition int32, offset int64, headers []*sarama.RecordHeader, b64 bool) []byte {
 	if m.Timestamp {
 		message = m.prependTimestamp(ts, message)
 	}
 
+	if m.Headers {
+		message = m.prependHeaders(headers, message)
+	}
+
 	if m.Key {
 		message = m.prependKey(key, message, b64)
 	}
@@ -69,6 +78,11 @@ func (m *MessageMetadata) SetIndentation() {
 		return
 	}
 
+	if m.Headers {
+		m.maxPrefixLength = len(headersPrefix)
+		return
+	}
+
 	if m.Offset {
 		m.maxPrefixLength = len(offsetPrefix)
 		return
@@ -113,6 +127,10 @@ func (m *MessageMetadata) prependKey(key []byte, in []byte, b64 bool) []byte {
 	return append([]byte(fmt.Sprintf("%s: %X\n", prefix, key)), in...)
 }
 
+func (m *MessageMetadata) prependHeaders(headers []*sarama.RecordHeader, in []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s: %s\n", m.getPrefix(headersPrefix), FormatHeaders(headers))), in...)
+}
+
 func (m *MessageMetadata) prependOffset(offset int64, in []byte) []byte {
 	return append([]byte(fmt.Sprintf("%s: %d\n", m.getPrefix(offsetPrefix), offset)), in...)
 }
@@ -120,3 +138,15 @@ func (m *MessageMetadata) prependOffset(offset int64, in []byte) []byte {
 func (m *MessageMetadata) prependPartition(partition int32, in []byte) []byte {
 	return append([]byte(fmt.Sprintf("%s: %d\n", m.getPrefix(partitionPrefix), partition)), in...)
 }
+
+// FormatHeaders returns the string representation of the message headers in "key=value" format, separated by commas.
+func FormatHeaders(headers []*sarama.RecordHeader) string {
+	pairs := make([]string, 0, len(headers))
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%s", header.Key, header.Value))
+	}
+	return strings.Join(pairs, ", ")
+}
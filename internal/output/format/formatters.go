@@ -64,6 +64,11 @@ func Red(input interface{}, colorEnabled bool) interface{} {
 	return colorIfEnabled(input, colorEnabled, text.FgHiRed)
 }
 
+// Green returns the input in green if coloring is enabled.
+func Green(input interface{}, colorEnabled bool) interface{} {
+	return colorIfEnabled(input, colorEnabled, text.FgHiGreen)
+}
+
 // RedIfTrue highlights the input in red, if coloring is enabled and the evaluation function returns true.
 func RedIfTrue(input interface{}, eval func() bool, colorEnabled bool) interface{} {
 	return colorIfEnabled(input, colorEnabled && eval(), text.FgHiRed)
@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Percentile returns the p-th percentile (0 to 100) of the durations using the nearest-rank method.
+//
+// The input slice will be sorted in place. Zero will be returned if the input is empty.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(durations) {
+		rank = len(durations)
+	}
+	return durations[rank-1]
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]time.Duration, 100)
+	for i := range hundred {
+		hundred[i] = time.Duration(100-i) * time.Millisecond
+	}
+	testCases := []struct {
+		title    string
+		input    []time.Duration
+		p        float64
+		expected time.Duration
+	}{
+		{
+			title:    "empty input",
+			p:        50,
+			expected: 0,
+		},
+		{
+			title:    "single value",
+			input:    []time.Duration{time.Second},
+			p:        99,
+			expected: time.Second,
+		},
+		{
+			title:    "median of unsorted values",
+			input:    []time.Duration{3 * time.Second, time.Second, 2 * time.Second},
+			p:        50,
+			expected: 2 * time.Second,
+		},
+		{
+			title:    "p50 of hundred values",
+			input:    hundred,
+			p:        50,
+			expected: 50 * time.Millisecond,
+		},
+		{
+			title:    "p99 of hundred values",
+			input:    hundred,
+			p:        99,
+			expected: 99 * time.Millisecond,
+		},
+		{
+			title:    "zero percentile",
+			input:    []time.Duration{2 * time.Second, time.Second},
+			p:        0,
+			expected: time.Second,
+		},
+		{
+			title:    "out of range percentile",
+			input:    []time.Duration{2 * time.Second, time.Second},
+			p:        150,
+			expected: 2 * time.Second,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual := Percentile(tC.input, tC.p)
+			if actual != tC.expected {
+				t.Errorf("Expected: %s, Actual: %s", tC.expected, actual)
+			}
+		})
+	}
+}
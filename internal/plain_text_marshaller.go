@@ -8,6 +8,10 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/xitonix/trubka/internal/filter"
 )
 
 const (
@@ -61,6 +65,7 @@ type PlainTextMarshaller struct {
 	inputEncoding  string
 	outputEncoding string
 	jsonProcessor  *JSONMessageProcessor
+	selector       *filter.Selector
 	isJSON         bool
 }
 
@@ -70,7 +75,8 @@ func NewPlainTextMarshaller(
 	outputEncoding string,
 	inclusions *MessageMetadata,
 	enableColor bool,
-	highlightStyle string) *PlainTextMarshaller {
+	highlightStyle string,
+	selector *filter.Selector) *PlainTextMarshaller {
 	outputEncoding = strings.TrimSpace(strings.ToLower(outputEncoding))
 	return &PlainTextMarshaller{
 		inputEncoding:  strings.TrimSpace(strings.ToLower(inputEncoding)),
@@ -81,20 +87,22 @@ func NewPlainTextMarshaller(
 			outputEncoding,
 			inclusions,
 			enableColor,
-			highlightStyle),
-		isJSON: outputEncoding == JSONEncoding || outputEncoding == JSONIndentEncoding,
+			highlightStyle,
+			selector),
+		selector: selector,
+		isJSON:   outputEncoding == JSONEncoding || outputEncoding == JSONIndentEncoding,
 	}
 }
 
 // Marshal marshals the Kafka message into plain text.
-func (m *PlainTextMarshaller) Marshal(msg, key []byte, ts time.Time, topic string, partition int32, offset int64) ([]byte, error) {
+func (m *PlainTextMarshaller) Marshal(msg, key []byte, ts time.Time, topic string, partition int32, offset int64, headers []*sarama.RecordHeader) ([]byte, error) {
 	result, mustEncode, err := m.decode(msg)
 	if err != nil {
 		return nil, err
 	}
 
 	if mustEncode {
-		result, err = m.encode(result, key, ts, topic, partition, offset)
+		result, err = m.encode(result, key, ts, topic, partition, offset, headers)
 		if err != nil {
 			return nil, err
 		}
@@ -103,11 +111,33 @@ func (m *PlainTextMarshaller) Marshal(msg, key []byte, ts time.Time, topic strin
 		}
 	}
 
-	result = m.inclusions.Render(key, result, ts, topic, partition, offset, m.outputEncoding == Base64Encoding)
+	result = m.inclusions.Render(key, result, ts, topic, partition, offset, headers, m.outputEncoding == Base64Encoding)
 
 	return result, nil
 }
 
+// Decode decodes the Kafka message based on the input encoding, regardless of the output encoding.
+func (m *PlainTextMarshaller) Decode(msg []byte) ([]byte, error) {
+	switch m.inputEncoding {
+	case HexEncoding:
+		buf := make([]byte, hex.DecodedLen(len(msg)))
+		n, err := hex.Decode(buf, msg)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case Base64Encoding:
+		buf := make([]byte, base64.StdEncoding.DecodedLen(len(msg)))
+		n, err := base64.StdEncoding.Decode(buf, msg)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return msg, nil
+	}
+}
+
 func (m *PlainTextMarshaller) decode(msg []byte) ([]byte, bool, error) {
 	switch m.inputEncoding {
 	case HexEncoding:
@@ -135,7 +165,7 @@ func (m *PlainTextMarshaller) decode(msg []byte) ([]byte, bool, error) {
 	}
 }
 
-func (m *PlainTextMarshaller) encode(decoded, key []byte, ts time.Time, topic string, partition int32, offset int64) ([]byte, error) {
+func (m *PlainTextMarshaller) encode(decoded, key []byte, ts time.Time, topic string, partition int32, offset int64, headers []*sarama.RecordHeader) ([]byte, error) {
 	switch m.outputEncoding {
 	case HexEncoding:
 		return m.marshalHex(decoded)
@@ -146,8 +176,11 @@ func (m *PlainTextMarshaller) encode(decoded, key []byte, ts time.Time, topic st
 		if err != nil {
 			return nil, err
 		}
-		return m.jsonProcessor.Process(result, key, ts, topic, partition, offset)
+		return m.jsonProcessor.Process(result, key, ts, topic, partition, offset, headers)
 	default:
+		if m.selector != nil {
+			return m.selector.SelectJSON(decoded, "")
+		}
 		return decoded, nil
 	}
 }
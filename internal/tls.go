@@ -0,0 +1,305 @@
+package internal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// TLSVersions the TLS versions which can be set as the minimum accepted version.
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ErrEncryptedKey is returned if the private key is encrypted, but no password has been provided.
+var ErrEncryptedKey = errors.New("the private key is encrypted, but no password has been provided")
+
+var (
+	oidPBES2      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+	cipherSuites  = map[string]uint16{}
+)
+
+func init() {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		// The TLS 1.3 cipher suites are not configurable.
+		if len(suite.SupportedVersions) == 1 && suite.SupportedVersions[0] == tls.VersionTLS13 {
+			continue
+		}
+		cipherSuites[suite.Name] = suite.ID
+	}
+}
+
+// ParseTLSVersion converts the version string (eg. 1.2) to its TLS constant.
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := TLSVersions[strings.TrimSpace(version)]
+	if !ok {
+		return 0, fmt.Errorf("invalid TLS version %q. Supported versions are 1.0, 1.1, 1.2 and 1.3", version)
+	}
+	return v, nil
+}
+
+// ParseCipherSuites converts the comma separated list of the cipher suite names (eg. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+// to their IDs.
+//
+// The cipher suites only apply to TLS 1.2 and earlier. The TLS 1.3 cipher suites are not configurable.
+func ParseCipherSuites(commaSeparated string) ([]uint16, error) {
+	var ids []uint16
+	for _, name := range strings.Split(commaSeparated, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		id, ok := cipherSuites[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS cipher suite %q. Supported cipher suites are %s", name, strings.Join(CipherSuiteNames(), ", "))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CipherSuiteNames returns the sorted list of the supported cipher suite names.
+func CipherSuiteNames() []string {
+	names := make([]string, 0, len(cipherSuites))
+	for name := range cipherSuites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadX509KeyPair loads the PEM encoded certificate and private key files.
+//
+// Encrypted private keys, either in the legacy OpenSSL format (Proc-Type: 4,ENCRYPTED) or in the PKCS#8 format
+// (ENCRYPTED PRIVATE KEY), will be decrypted using the password.
+func LoadX509KeyPair(certFile, keyFile, password string) (tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	var block *pem.Block
+	for rest := keyPEM; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil || strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			break
+		}
+	}
+	if block == nil {
+		return tls.Certificate{}, fmt.Errorf("no PEM encoded private key has been found in %s", keyFile)
+	}
+	der, err := decryptPEMKey(block, password)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// LoadPKCS12 loads the client certificate, its chain and the private key from the PKCS#12 (.p12 or .pfx) bundle.
+func LoadPKCS12(path, password string) (tls.Certificate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	blocks, err := pkcs12.ToPEM(content, password)
+	if err != nil {
+		var notImplemented pkcs12.NotImplementedError
+		if errors.As(err, &notImplemented) {
+			return tls.Certificate{}, fmt.Errorf("%w. The bundles created by OpenSSL 3 must be exported using the -legacy option", err)
+		}
+		return tls.Certificate{}, err
+	}
+	var (
+		keyPEM []byte
+		certs  [][]byte
+	)
+	for _, block := range blocks {
+		// The attributes of the bags are returned as PEM headers.
+		b := &pem.Block{Type: block.Type, Bytes: block.Bytes}
+		switch block.Type {
+		case "PRIVATE KEY":
+			keyPEM = pem.EncodeToMemory(b)
+		case "CERTIFICATE":
+			certs = append(certs, pem.EncodeToMemory(b))
+		}
+	}
+	if keyPEM == nil || len(certs) == 0 {
+		return tls.Certificate{}, errors.New("the PKCS#12 bundle must contain both the client certificate and the private key")
+	}
+	// The leaf certificate must be the first certificate of the chain.
+	for i, leaf := range certs {
+		certificate, err := tls.X509KeyPair(leaf, keyPEM)
+		if err != nil {
+			continue
+		}
+		for j, cert := range certs {
+			if i != j {
+				block, _ := pem.Decode(cert)
+				certificate.Certificate = append(certificate.Certificate, block.Bytes)
+			}
+		}
+		return certificate, nil
+	}
+	return tls.Certificate{}, errors.New("none of the certificates in the PKCS#12 bundle matches the private key")
+}
+
+func decryptPEMKey(block *pem.Block, password string) ([]byte, error) {
+	//nolint:staticcheck // RFC 1423 is insecure by design, but the legacy keys are still generated by the older versions of OpenSSL.
+	legacy := x509.IsEncryptedPEMBlock(block)
+	if !legacy && block.Type != "ENCRYPTED PRIVATE KEY" {
+		return block.Bytes, nil
+	}
+	if password == "" {
+		return nil, ErrEncryptedKey
+	}
+	if legacy {
+		//nolint:staticcheck // The standard library has no non-deprecated alternative for decrypting RFC 1423 keys.
+		der, err := x509.DecryptPEMBlock(block, []byte(password))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the private key: %w", err)
+		}
+		return der, nil
+	}
+	return decryptPKCS8(block.Bytes, password)
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts the PKCS#8 private keys, encrypted using the PBES2 scheme with PBKDF2 key derivation.
+func decryptPKCS8(der []byte, password string) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption scheme %s. Only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("invalid PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s. Only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: %w", err)
+	}
+
+	var prf func() hash.Hash
+	switch algorithm := kdf.PRF.Algorithm; {
+	case len(algorithm) == 0, algorithm.Equal(oidHMACSHA1):
+		prf = sha1.New
+	case algorithm.Equal(oidHMACSHA224):
+		prf = sha256.New224
+	case algorithm.Equal(oidHMACSHA256):
+		prf = sha256.New
+	case algorithm.Equal(oidHMACSHA384):
+		prf = sha512.New384
+	case algorithm.Equal(oidHMACSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", algorithm)
+	}
+
+	var (
+		keyLength int
+		newCipher func(key []byte) (cipher.Block, error)
+	)
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keyLength, newCipher = 16, aes.NewCipher
+	case scheme.Equal(oidAES192CBC):
+		keyLength, newCipher = 24, aes.NewCipher
+	case scheme.Equal(oidAES256CBC):
+		keyLength, newCipher = 32, aes.NewCipher
+	case scheme.Equal(oidDESEDE3CBC):
+		keyLength, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("unsupported private key cipher %s", scheme)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("invalid private key cipher parameters: %w", err)
+	}
+
+	key, err := pbkdf2.Key(prf, password, kdf.Salt, kdf.IterationCount, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the private key encryption key: %w", err)
+	}
+	block, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid encrypted private key")
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	// A wrong password is detected by the invalid padding or the malformed decrypted key.
+	errIncorrectPassword := errors.New("failed to decrypt the private key: incorrect password")
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > block.BlockSize() {
+		return nil, errIncorrectPassword
+	}
+	for _, b := range decrypted[len(decrypted)-padding:] {
+		if int(b) != padding {
+			return nil, errIncorrectPassword
+		}
+	}
+	decrypted = decrypted[:len(decrypted)-padding]
+	if _, err := x509.ParsePKCS8PrivateKey(decrypted); err != nil {
+		return nil, errIncorrectPassword
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenBucket represents a token bucket rate limiter which is safe for concurrent use.
+type TokenBucket struct {
+	mux    sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a new token bucket which refills at the specified rate per second.
+//
+// The bucket starts full and can hold up to burst tokens. Burst values less than one will be set to one.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// Wait blocks until a token is available or the context is cancelled.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token from the bucket and returns the amount of time the caller needs to wait before using it.
+func (b *TokenBucket) reserve(now time.Time) time.Duration {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.last.IsZero() {
+		b.last = now
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// ParseRate parses a rate string in N/unit format (eg. 100/s, 500/m or 1000/h) and returns the rate per second.
+//
+// The unit is optional and defaults to seconds.
+func ParseRate(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	number, unit := value, "s"
+	if i := strings.Index(value, "/"); i >= 0 {
+		number, unit = strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+	}
+	rate, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return 0, fmt.Errorf("invalid rate %q. The rate must be in N/unit format (eg. 100/s)", value)
+	}
+	if rate <= 0 {
+		return 0, errors.New("the rate must be greater than zero")
+	}
+	switch unit {
+	case "s":
+		return rate, nil
+	case "m":
+		return rate / 60, nil
+	case "h":
+		return rate / 3600, nil
+	default:
+		return 0, fmt.Errorf("invalid rate unit %q. The unit must be s, m or h", unit)
+	}
+}
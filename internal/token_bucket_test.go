@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		title    string
+		rate     float64
+		burst    int
+		calls    []time.Duration
+		expected []time.Duration
+	}{
+		{
+			title:    "consecutive calls",
+			rate:     10,
+			burst:    1,
+			calls:    []time.Duration{0, 0, 0},
+			expected: []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			title:    "refill over time",
+			rate:     10,
+			burst:    1,
+			calls:    []time.Duration{0, 0, 0, 200 * time.Millisecond},
+			expected: []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			title:    "burst",
+			rate:     2,
+			burst:    3,
+			calls:    []time.Duration{0, 0, 0, 0},
+			expected: []time.Duration{0, 0, 0, 500 * time.Millisecond},
+		},
+		{
+			title:    "tokens do not exceed burst",
+			rate:     10,
+			burst:    2,
+			calls:    []time.Duration{0, time.Hour, time.Hour, time.Hour, time.Hour},
+			expected: []time.Duration{0, 0, 0, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			title:    "zero burst",
+			rate:     4,
+			burst:    0,
+			calls:    []time.Duration{0, 0},
+			expected: []time.Duration{0, 250 * time.Millisecond},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			bucket := NewTokenBucket(tC.rate, tC.burst)
+			for i, offset := range tC.calls {
+				actual := bucket.reserve(start.Add(offset))
+				if actual != tC.expected[i] {
+					t.Errorf("Call %d: Expected delay: %s, Actual: %s", i, tC.expected[i], actual)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         string
+		expected      float64
+		expectedError string
+	}{
+		{
+			title:    "per second",
+			input:    "100/s",
+			expected: 100,
+		},
+		{
+			title:    "no unit",
+			input:    "2.5",
+			expected: 2.5,
+		},
+		{
+			title:    "per minute",
+			input:    " 120 / m ",
+			expected: 2,
+		},
+		{
+			title:    "per hour",
+			input:    "7200/h",
+			expected: 2,
+		},
+		{
+			title:         "invalid number",
+			input:         "fast/s",
+			expectedError: `invalid rate "fast/s". The rate must be in N/unit format (eg. 100/s)`,
+		},
+		{
+			title:         "empty input",
+			input:         "",
+			expectedError: `invalid rate "". The rate must be in N/unit format (eg. 100/s)`,
+		},
+		{
+			title:         "zero rate",
+			input:         "0/s",
+			expectedError: "the rate must be greater than zero",
+		},
+		{
+			title:         "invalid unit",
+			input:         "10/d",
+			expectedError: `invalid rate unit "d". The unit must be s, m or h`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := ParseRate(tC.input)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected rate: %v, Actual: %v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func checkError(actual error, expected string) bool {
+	if actual == nil {
+		return expected == ""
+	}
+	if expected == "" {
+		return false
+	}
+	return strings.Contains(actual.Error(), expected)
+}
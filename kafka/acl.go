@@ -0,0 +1,217 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// clusterResourceName the name of the cluster resource in Kafka ACLs.
+const clusterResourceName = "kafka-cluster"
+
+// ACL represents a Kafka access control list entry.
+//
+// The same type is used to filter the ACL entries, in which case the empty fields match any value.
+type ACL struct {
+	// ResourceType the resource type (eg. topic, group, cluster, transactional-id or delegation-token).
+	ResourceType string `json:"resource_type"`
+	// ResourceName the resource name.
+	ResourceName string `json:"resource_name"`
+	// PatternType the resource pattern type (eg. literal, prefixed or match).
+	PatternType string `json:"pattern_type"`
+	// Principal the principal to which the entry applies (eg. User:alice).
+	Principal string `json:"principal"`
+	// Host the host from which the principal connects.
+	Host string `json:"host"`
+	// Operation the operation (eg. read, write, describe etc).
+	Operation string `json:"operation"`
+	// Permission the permission type (allow or deny).
+	Permission string `json:"permission"`
+}
+
+// ACLsByResource sorts the ACL entries by resource, principal and operation.
+type ACLsByResource []*ACL
+
+func (a ACLsByResource) Len() int {
+	return len(a)
+}
+
+func (a ACLsByResource) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+
+func (a ACLsByResource) Less(i, j int) bool {
+	if a[i].ResourceType != a[j].ResourceType {
+		return a[i].ResourceType < a[j].ResourceType
+	}
+	if a[i].ResourceName != a[j].ResourceName {
+		return a[i].ResourceName < a[j].ResourceName
+	}
+	if a[i].Principal != a[j].Principal {
+		return a[i].Principal < a[j].Principal
+	}
+	return a[i].Operation < a[j].Operation
+}
+
+// MatchesAll returns true if the entry, as a filter, matches all the ACL entries in the cluster.
+//
+// The explicit match-all values (eg. any resource type or the match pattern type without a resource name) are treated
+// the same as the empty fields. Invalid filters do not match anything.
+func (a *ACL) MatchesAll() bool {
+	filter, err := a.toFilter()
+	if err != nil {
+		return false
+	}
+	return filter.ResourceType == sarama.AclResourceAny &&
+		filter.ResourceName == nil &&
+		(filter.ResourcePatternTypeFilter == sarama.AclPatternAny || filter.ResourcePatternTypeFilter == sarama.AclPatternMatch) &&
+		filter.Principal == nil &&
+		filter.Host == nil &&
+		filter.Operation == sarama.AclOperationAny &&
+		filter.PermissionType == sarama.AclPermissionAny
+}
+
+// toFilter converts the entry to an ACL filter. The empty fields will match any value.
+func (a *ACL) toFilter() (sarama.AclFilter, error) {
+	filter := sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		Operation:                 sarama.AclOperationAny,
+		PermissionType:            sarama.AclPermissionAny,
+		ResourceName:              nullableString(a.ResourceName),
+		Principal:                 nullableString(a.Principal),
+		Host:                      nullableString(a.Host),
+	}
+	if err := parseACLValue(a.ResourceType, &filter.ResourceType); err != nil {
+		return filter, err
+	}
+	if err := parseACLValue(a.PatternType, &filter.ResourcePatternTypeFilter); err != nil {
+		return filter, err
+	}
+	if err := parseACLValue(a.Operation, &filter.Operation); err != nil {
+		return filter, err
+	}
+	if err := parseACLValue(a.Permission, &filter.PermissionType); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// toBinding converts the entry to an ACL binding.
+//
+// The pattern type and the host are set to literal and wildcard (*) respectively, if not specified.
+func (a *ACL) toBinding() (sarama.Resource, sarama.Acl, error) {
+	resource := sarama.Resource{
+		ResourceName:        strings.TrimSpace(a.ResourceName),
+		ResourcePatternType: sarama.AclPatternLiteral,
+	}
+	acl := sarama.Acl{
+		Principal: strings.TrimSpace(a.Principal),
+		Host:      strings.TrimSpace(a.Host),
+	}
+	if acl.Host == "" {
+		acl.Host = "*"
+	}
+	if acl.Principal == "" {
+		return resource, acl, errors.New("the principal cannot be empty")
+	}
+	if err := parseRequiredACLValue(a.ResourceType, "resource type", &resource.ResourceType); err != nil {
+		return resource, acl, err
+	}
+	if resource.ResourceName == "" {
+		if resource.ResourceType != sarama.AclResourceCluster {
+			return resource, acl, errors.New("the resource name cannot be empty")
+		}
+		resource.ResourceName = clusterResourceName
+	}
+	if err := parseACLValue(a.PatternType, &resource.ResourcePatternType); err != nil {
+		return resource, acl, err
+	}
+	if err := parseRequiredACLValue(a.Operation, "operation", &acl.Operation); err != nil {
+		return resource, acl, err
+	}
+	if err := parseRequiredACLValue(a.Permission, "permission", &acl.PermissionType); err != nil {
+		return resource, acl, err
+	}
+	if resource.ResourcePatternType != sarama.AclPatternLiteral && resource.ResourcePatternType != sarama.AclPatternPrefixed {
+		return resource, acl, fmt.Errorf("%s is not a valid pattern type to create ACLs. Use literal or prefixed instead", a.PatternType)
+	}
+	if resource.ResourceType == sarama.AclResourceAny || acl.Operation == sarama.AclOperationAny || acl.PermissionType == sarama.AclPermissionAny {
+		return resource, acl, errors.New("'any' is only acceptable for filtering ACLs")
+	}
+	return resource, acl, nil
+}
+
+func fromResourceACLs(resources []sarama.ResourceAcls) []*ACL {
+	result := make([]*ACL, 0)
+	for _, resource := range resources {
+		for _, acl := range resource.Acls {
+			result = append(result, newACL(resource.Resource, acl))
+		}
+	}
+	sort.Sort(ACLsByResource(result))
+	return result
+}
+
+func newACL(resource sarama.Resource, acl *sarama.Acl) *ACL {
+	return &ACL{
+		ResourceType: aclValueString(resource.ResourceType.String()),
+		ResourceName: resource.ResourceName,
+		PatternType:  aclValueString(resource.ResourcePatternType.String()),
+		Principal:    acl.Principal,
+		Host:         acl.Host,
+		Operation:    aclValueString(acl.Operation.String()),
+		Permission:   aclValueString(acl.PermissionType.String()),
+	}
+}
+
+// aclValueString converts the sarama ACL values to lower-case, hyphenated strings (eg. TransactionalID -> transactional-id).
+func aclValueString(value string) string {
+	switch value {
+	case "TransactionalID":
+		return "transactional-id"
+	case "DelegationToken":
+		return "delegation-token"
+	case "ClusterAction":
+		return "cluster-action"
+	case "DescribeConfigs":
+		return "describe-configs"
+	case "AlterConfigs":
+		return "alter-configs"
+	case "IdempotentWrite":
+		return "idempotent-write"
+	default:
+		return strings.ToLower(value)
+	}
+}
+
+type aclValue interface {
+	UnmarshalText(text []byte) error
+}
+
+// parseACLValue parses the ACL value into the target, if the value is not empty.
+func parseACLValue(value string, target aclValue) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return target.UnmarshalText([]byte(strings.ReplaceAll(value, "-", "")))
+}
+
+func parseRequiredACLValue(value, name string, target aclValue) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("the %s cannot be empty", name)
+	}
+	return parseACLValue(value, target)
+}
+
+func nullableString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
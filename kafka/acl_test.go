@@ -0,0 +1,283 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestACLToFilter(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         *ACL
+		expected      sarama.AclFilter
+		expectedError string
+	}{
+		{
+			title: "empty filter matches everything",
+			input: &ACL{},
+			expected: sarama.AclFilter{
+				ResourceType:              sarama.AclResourceAny,
+				ResourcePatternTypeFilter: sarama.AclPatternAny,
+				Operation:                 sarama.AclOperationAny,
+				PermissionType:            sarama.AclPermissionAny,
+			},
+		},
+		{
+			title: "hyphenated values",
+			input: &ACL{
+				ResourceType: "transactional-id",
+				ResourceName: "tx",
+				PatternType:  "prefixed",
+				Principal:    "User:alice",
+				Host:         "127.0.0.1",
+				Operation:    "idempotent-write",
+				Permission:   "deny",
+			},
+			expected: sarama.AclFilter{
+				ResourceType:              sarama.AclResourceTransactionalID,
+				ResourceName:              nullableString("tx"),
+				ResourcePatternTypeFilter: sarama.AclPatternPrefixed,
+				Principal:                 nullableString("User:alice"),
+				Host:                      nullableString("127.0.0.1"),
+				Operation:                 sarama.AclOperationIdempotentWrite,
+				PermissionType:            sarama.AclPermissionDeny,
+			},
+		},
+		{
+			title:         "invalid resource type",
+			input:         &ACL{ResourceType: "table"},
+			expectedError: "no acl resource with name table",
+		},
+		{
+			title:         "invalid operation",
+			input:         &ACL{Operation: "execute"},
+			expectedError: "no acl operation with name execute",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := tC.input.toFilter()
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if actual.ResourceType != tC.expected.ResourceType ||
+				actual.ResourcePatternTypeFilter != tC.expected.ResourcePatternTypeFilter ||
+				actual.Operation != tC.expected.Operation ||
+				actual.PermissionType != tC.expected.PermissionType ||
+				!equalNullableStrings(actual.ResourceName, tC.expected.ResourceName) ||
+				!equalNullableStrings(actual.Principal, tC.expected.Principal) ||
+				!equalNullableStrings(actual.Host, tC.expected.Host) {
+				t.Errorf("Expected filter: %+v, Actual: %+v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestACLMatchesAll(t *testing.T) {
+	testCases := []struct {
+		title    string
+		input    *ACL
+		expected bool
+	}{
+		{
+			title:    "empty filter",
+			input:    &ACL{},
+			expected: true,
+		},
+		{
+			title: "explicit match-all values",
+			input: &ACL{
+				ResourceType: "any",
+				PatternType:  "any",
+				Operation:    "any",
+				Permission:   "any",
+			},
+			expected: true,
+		},
+		{
+			title:    "match pattern type without resource name",
+			input:    &ACL{ResourceType: "any", PatternType: "match", ResourceName: " "},
+			expected: true,
+		},
+		{
+			title:    "resource type",
+			input:    &ACL{ResourceType: "topic"},
+			expected: false,
+		},
+		{
+			title:    "resource name",
+			input:    &ACL{ResourceType: "any", ResourceName: "events"},
+			expected: false,
+		},
+		{
+			title:    "principal",
+			input:    &ACL{Principal: "User:alice"},
+			expected: false,
+		},
+		{
+			title:    "host",
+			input:    &ACL{Host: "*"},
+			expected: false,
+		},
+		{
+			title:    "operation",
+			input:    &ACL{Operation: "all"},
+			expected: false,
+		},
+		{
+			title:    "permission",
+			input:    &ACL{Permission: "allow"},
+			expected: false,
+		},
+		{
+			title:    "pattern type",
+			input:    &ACL{PatternType: "literal"},
+			expected: false,
+		},
+		{
+			title:    "invalid filter",
+			input:    &ACL{Operation: "execute"},
+			expected: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual := tC.input.MatchesAll()
+			if actual != tC.expected {
+				t.Errorf("Expected: %v, Actual: %v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestACLToBinding(t *testing.T) {
+	testCases := []struct {
+		title            string
+		input            *ACL
+		expectedResource sarama.Resource
+		expectedACL      sarama.Acl
+		expectedError    string
+	}{
+		{
+			title: "defaults",
+			input: &ACL{
+				ResourceType: "topic",
+				ResourceName: "events",
+				Principal:    "User:alice",
+				Operation:    "read",
+				Permission:   "allow",
+			},
+			expectedResource: sarama.Resource{
+				ResourceType:        sarama.AclResourceTopic,
+				ResourceName:        "events",
+				ResourcePatternType: sarama.AclPatternLiteral,
+			},
+			expectedACL: sarama.Acl{
+				Principal:      "User:alice",
+				Host:           "*",
+				Operation:      sarama.AclOperationRead,
+				PermissionType: sarama.AclPermissionAllow,
+			},
+		},
+		{
+			title: "cluster resource name",
+			input: &ACL{
+				ResourceType: "cluster",
+				PatternType:  "literal",
+				Principal:    "User:alice",
+				Host:         "10.0.0.1",
+				Operation:    "cluster-action",
+				Permission:   "deny",
+			},
+			expectedResource: sarama.Resource{
+				ResourceType:        sarama.AclResourceCluster,
+				ResourceName:        clusterResourceName,
+				ResourcePatternType: sarama.AclPatternLiteral,
+			},
+			expectedACL: sarama.Acl{
+				Principal:      "User:alice",
+				Host:           "10.0.0.1",
+				Operation:      sarama.AclOperationClusterAction,
+				PermissionType: sarama.AclPermissionDeny,
+			},
+		},
+		{
+			title:         "missing principal",
+			input:         &ACL{ResourceType: "topic", ResourceName: "events", Operation: "read", Permission: "allow"},
+			expectedError: "the principal cannot be empty",
+		},
+		{
+			title:         "missing resource name",
+			input:         &ACL{ResourceType: "topic", Principal: "User:alice", Operation: "read", Permission: "allow"},
+			expectedError: "the resource name cannot be empty",
+		},
+		{
+			title:         "missing operation",
+			input:         &ACL{ResourceType: "topic", ResourceName: "events", Principal: "User:alice", Permission: "allow"},
+			expectedError: "the operation cannot be empty",
+		},
+		{
+			title:         "match pattern type",
+			input:         &ACL{ResourceType: "topic", ResourceName: "events", PatternType: "match", Principal: "User:alice", Operation: "read", Permission: "allow"},
+			expectedError: "match is not a valid pattern type to create ACLs",
+		},
+		{
+			title:         "any operation",
+			input:         &ACL{ResourceType: "topic", ResourceName: "events", Principal: "User:alice", Operation: "any", Permission: "allow"},
+			expectedError: "'any' is only acceptable for filtering ACLs",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			resource, acl, err := tC.input.toBinding()
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if resource != tC.expectedResource {
+				t.Errorf("Expected resource: %+v, Actual: %+v", tC.expectedResource, resource)
+			}
+			if acl != tC.expectedACL {
+				t.Errorf("Expected ACL: %+v, Actual: %+v", tC.expectedACL, acl)
+			}
+		})
+	}
+}
+
+func TestNewACL(t *testing.T) {
+	actual := newACL(sarama.Resource{
+		ResourceType:        sarama.AclResourceDelegationToken,
+		ResourceName:        "token",
+		ResourcePatternType: sarama.AclPatternPrefixed,
+	}, &sarama.Acl{
+		Principal:      "User:alice",
+		Host:           "*",
+		Operation:      sarama.AclOperationDescribeConfigs,
+		PermissionType: sarama.AclPermissionAllow,
+	})
+	expected := ACL{
+		ResourceType: "delegation-token",
+		ResourceName: "token",
+		PatternType:  "prefixed",
+		Principal:    "User:alice",
+		Host:         "*",
+		Operation:    "describe-configs",
+		Permission:   "allow",
+	}
+	if *actual != expected {
+		t.Errorf("Expected: %+v, Actual: %+v", expected, *actual)
+	}
+}
+
+func equalNullableStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
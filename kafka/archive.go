@@ -0,0 +1,332 @@
+package kafka
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	// JSONArchive newline delimited Json archive format.
+	JSONArchive = "json"
+	// BinaryArchive length-prefixed binary archive format.
+	BinaryArchive = "binary"
+)
+
+// binaryArchiveMagic the header of the binary archives, followed by the format version.
+var binaryArchiveMagic = []byte("TRBK\x01")
+
+// maxArchiveRecordSize the maximum acceptable size of a binary archive record.
+const maxArchiveRecordSize = 256 << 20
+
+type archivedHeader struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// archivedEvent the Json representation of an archived event. The key, value and header values are base64 encoded.
+type archivedEvent struct {
+	Topic     string            `json:"topic"`
+	Partition int32             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Timestamp time.Time         `json:"timestamp"`
+	Key       []byte            `json:"key"`
+	Value     []byte            `json:"value"`
+	Headers   []*archivedHeader `json:"headers,omitempty"`
+}
+
+// ArchiveWriter writes Kafka events to an archive.
+type ArchiveWriter struct {
+	writer *bufio.Writer
+	format string
+}
+
+// NewArchiveWriter creates a new archive writer.
+func NewArchiveWriter(w io.Writer, format string) (*ArchiveWriter, error) {
+	writer := bufio.NewWriter(w)
+	switch format {
+	case JSONArchive:
+	case BinaryArchive:
+		if _, err := writer.Write(binaryArchiveMagic); err != nil {
+			return nil, fmt.Errorf("failed to write the archive header: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid archive format %q", format)
+	}
+	return &ArchiveWriter{
+		writer: writer,
+		format: format,
+	}, nil
+}
+
+// Write writes the event to the archive.
+func (a *ArchiveWriter) Write(event *Event) error {
+	if a.format == JSONArchive {
+		data, err := json.Marshal(toArchivedEvent(event))
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		_, err = a.writer.Write(data)
+		return err
+	}
+
+	record := encodeBinaryEvent(event)
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(record)))
+	if _, err := a.writer.Write(size[:]); err != nil {
+		return err
+	}
+	_, err := a.writer.Write(record)
+	return err
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (a *ArchiveWriter) Flush() error {
+	return a.writer.Flush()
+}
+
+// ArchiveReader reads Kafka events from an archive.
+type ArchiveReader struct {
+	reader *bufio.Reader
+	format string
+}
+
+// NewArchiveReader creates a new archive reader. The format of the archive will be detected automatically.
+func NewArchiveReader(r io.Reader) (*ArchiveReader, error) {
+	reader := bufio.NewReader(r)
+	format := JSONArchive
+	header, err := reader.Peek(len(binaryArchiveMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read the archive: %w", err)
+	}
+	if bytes.Equal(header, binaryArchiveMagic) {
+		format = BinaryArchive
+		_, _ = reader.Discard(len(binaryArchiveMagic))
+	}
+	return &ArchiveReader{
+		reader: reader,
+		format: format,
+	}, nil
+}
+
+// Format returns the format of the archive.
+func (a *ArchiveReader) Format() string {
+	return a.format
+}
+
+// Read reads the next event from the archive. It returns io.EOF when there are no more events to read.
+func (a *ArchiveReader) Read() (*Event, error) {
+	if a.format == JSONArchive {
+		return a.readJSON()
+	}
+	return a.readBinary()
+}
+
+func (a *ArchiveReader) readJSON() (*Event, error) {
+	for {
+		line, err := a.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var archived archivedEvent
+		if jErr := json.Unmarshal(line, &archived); jErr != nil {
+			return nil, fmt.Errorf("invalid archived event: %w", jErr)
+		}
+		return archived.toEvent(), nil
+	}
+}
+
+func (a *ArchiveReader) readBinary() (*Event, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(a.reader, size[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("invalid archived event: unexpected end of the archive")
+		}
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(size[:])
+	if length > maxArchiveRecordSize {
+		return nil, fmt.Errorf("invalid archived event: the record size (%d bytes) exceeds the limit", length)
+	}
+	record := make([]byte, length)
+	if _, err := io.ReadFull(a.reader, record); err != nil {
+		return nil, errors.New("invalid archived event: unexpected end of the archive")
+	}
+	event, err := decodeBinaryEvent(record)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archived event: %w", err)
+	}
+	return event, nil
+}
+
+func toArchivedEvent(event *Event) *archivedEvent {
+	archived := &archivedEvent{
+		Topic:     event.Topic,
+		Partition: event.Partition,
+		Offset:    event.Offset,
+		Timestamp: event.Timestamp,
+		Key:       event.Key,
+		Value:     event.Value,
+	}
+	for _, header := range event.Headers {
+		if header == nil {
+			continue
+		}
+		archived.Headers = append(archived.Headers, &archivedHeader{
+			Key:   string(header.Key),
+			Value: header.Value,
+		})
+	}
+	return archived
+}
+
+func (a *archivedEvent) toEvent() *Event {
+	event := &Event{
+		Topic:     a.Topic,
+		Key:       a.Key,
+		Value:     a.Value,
+		Timestamp: a.Timestamp,
+		Partition: a.Partition,
+		Offset:    a.Offset,
+	}
+	for _, header := range a.Headers {
+		if header == nil {
+			continue
+		}
+		event.Headers = append(event.Headers, &sarama.RecordHeader{
+			Key:   []byte(header.Key),
+			Value: header.Value,
+		})
+	}
+	return event
+}
+
+// encodeBinaryEvent encodes the event in the following big-endian layout:
+//
+// topic (bytes), partition (int32), offset (int64), timestamp (int64, Unix nanoseconds, zero if not set),
+// key (bytes), value (bytes), header count (int32), followed by the key (bytes) and the value (bytes) of each header.
+//
+// Each bytes field is prefixed with its int32 length, which is set to -1 for nil values.
+func encodeBinaryEvent(event *Event) []byte {
+	var buf bytes.Buffer
+	putBytes(&buf, []byte(event.Topic))
+	putInt(&buf, event.Partition)
+	putInt(&buf, event.Offset)
+	var ts int64
+	if !event.Timestamp.IsZero() {
+		ts = event.Timestamp.UnixNano()
+	}
+	putInt(&buf, ts)
+	putBytes(&buf, event.Key)
+	putBytes(&buf, event.Value)
+	headers := make([]*sarama.RecordHeader, 0, len(event.Headers))
+	for _, header := range event.Headers {
+		if header != nil {
+			headers = append(headers, header)
+		}
+	}
+	putInt(&buf, int32(len(headers)))
+	for _, header := range headers {
+		putBytes(&buf, header.Key)
+		putBytes(&buf, header.Value)
+	}
+	return buf.Bytes()
+}
+
+func decodeBinaryEvent(record []byte) (*Event, error) {
+	r := bytes.NewReader(record)
+	event := &Event{}
+	topic, err := readBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	event.Topic = string(topic)
+	if err := binary.Read(r, binary.BigEndian, &event.Partition); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &event.Offset); err != nil {
+		return nil, err
+	}
+	var ts int64
+	if err := binary.Read(r, binary.BigEndian, &ts); err != nil {
+		return nil, err
+	}
+	if ts != 0 {
+		event.Timestamp = time.Unix(0, ts)
+	}
+	if event.Key, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	if event.Value, err = readBytes(r); err != nil {
+		return nil, err
+	}
+	var count int32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	if count < 0 || int(count) > r.Len() {
+		return nil, fmt.Errorf("invalid header count %d", count)
+	}
+	for i := int32(0); i < count; i++ {
+		key, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		event.Headers = append(event.Headers, &sarama.RecordHeader{
+			Key:   key,
+			Value: value,
+		})
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("%d unexpected trailing bytes", r.Len())
+	}
+	return event, nil
+}
+
+func putInt(buf *bytes.Buffer, value interface{}) {
+	// Writing fixed size integers to a bytes buffer never fails.
+	_ = binary.Write(buf, binary.BigEndian, value)
+}
+
+func putBytes(buf *bytes.Buffer, value []byte) {
+	if value == nil {
+		putInt(buf, int32(-1))
+		return
+	}
+	putInt(buf, int32(len(value)))
+	buf.Write(value)
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	var length int32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, nil
+	}
+	if int64(length) > int64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,129 @@
+package kafka
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	events := []*Event{
+		{
+			Topic:     "events",
+			Key:       []byte("key"),
+			Value:     []byte(`{"name":"value"}`),
+			Timestamp: time.Date(2020, 7, 15, 14, 24, 23, 123456789, time.UTC),
+			Partition: 2,
+			Offset:    100,
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("trace-id"), Value: []byte("123")},
+				{Key: []byte("empty"), Value: nil},
+			},
+		},
+		{
+			Topic:     "events",
+			Value:     []byte{0, 1, 2, '\n', 255},
+			Partition: 0,
+			Offset:    7,
+		},
+		{
+			Topic: "empty",
+			Key:   []byte{},
+			Value: []byte{},
+		},
+	}
+	for _, format := range []string{JSONArchive, BinaryArchive} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewArchiveWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("Failed to create the archive writer: %s", err)
+			}
+			for _, event := range events {
+				if err := writer.Write(event); err != nil {
+					t.Fatalf("Failed to write the event: %s", err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("Failed to flush the archive: %s", err)
+			}
+
+			reader, err := NewArchiveReader(&buf)
+			if err != nil {
+				t.Fatalf("Failed to create the archive reader: %s", err)
+			}
+			if reader.Format() != format {
+				t.Errorf("Expected archive format: %s, Actual: %s", format, reader.Format())
+			}
+			for _, expected := range events {
+				actual, err := reader.Read()
+				if err != nil {
+					t.Fatalf("Failed to read the event: %s", err)
+				}
+				if !actual.Timestamp.Equal(expected.Timestamp) {
+					t.Errorf("Expected timestamp: %v, Actual: %v", expected.Timestamp, actual.Timestamp)
+				}
+				actual.Timestamp = expected.Timestamp
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("Expected event: %+v, Actual: %+v", expected, actual)
+				}
+			}
+			if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+				t.Errorf("Expected io.EOF, Actual: %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveReaderInvalidInput(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         []byte
+		expectedError string
+	}{
+		{
+			title:         "invalid json",
+			input:         []byte("{\"topic\":\n"),
+			expectedError: "invalid archived event: unexpected end of JSON input",
+		},
+		{
+			title:         "truncated binary size",
+			input:         append(append([]byte{}, binaryArchiveMagic...), 0, 0),
+			expectedError: "invalid archived event: unexpected end of the archive",
+		},
+		{
+			title:         "truncated binary record",
+			input:         append(append([]byte{}, binaryArchiveMagic...), 0, 0, 0, 10, 1),
+			expectedError: "invalid archived event: unexpected end of the archive",
+		},
+		{
+			title:         "invalid binary record",
+			input:         append(append([]byte{}, binaryArchiveMagic...), 0, 0, 0, 4, 0, 0, 0, 10),
+			expectedError: "invalid archived event: unexpected EOF",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			reader, err := NewArchiveReader(bytes.NewReader(tC.input))
+			if err != nil {
+				t.Fatalf("Failed to create the archive reader: %s", err)
+			}
+			_, err = reader.Read()
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestNewArchiveWriterInvalidFormat(t *testing.T) {
+	_, err := NewArchiveWriter(io.Discard, "xml")
+	if !checkError(err, `invalid archive format "xml"`) {
+		t.Errorf("Expected invalid format error, Actual: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// DeliveryReport represents the delivery result of an asynchronously published message.
+type DeliveryReport struct {
+	// Partition the partition to which the message has been published.
+	Partition int32
+	// Offset the offset of the published message.
+	Offset int64
+	// Latency the time it took for the message to be acknowledged by the broker.
+	Latency time.Duration
+	// Err the reason of the failure, if the message could not be published.
+	Err error
+}
+
+// AsyncProducer represents a wrapper around Sarama async producer.
+type AsyncProducer struct {
+	producer sarama.AsyncProducer
+	reports  chan *DeliveryReport
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// NewAsyncProducer creates a new instance of Kafka async producer.
+//
+// The delivery reports must be drained by the caller, otherwise the producer will be blocked.
+func NewAsyncProducer(brokers []string, options ...Option) (*AsyncProducer, error) {
+	client, err := initClient(brokers, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &AsyncProducer{
+		producer: producer,
+		reports:  make(chan *DeliveryReport, 1000),
+	}
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		for msg := range producer.Successes() {
+			p.reports <- newDeliveryReport(msg, nil)
+		}
+	}()
+	go func() {
+		defer p.wg.Done()
+		for pErr := range producer.Errors() {
+			p.reports <- newDeliveryReport(pErr.Msg, pErr.Err)
+		}
+	}()
+	go func() {
+		p.wg.Wait()
+		close(p.reports)
+	}()
+	return p, nil
+}
+
+// Produce queues a new message to be published to the specified Kafka topic.
+//
+// The partition will be selected based on the key, if the requested partition is negative.
+// The call blocks if the internal buffers of the producer are full.
+func (p *AsyncProducer) Produce(ctx context.Context, topic string, key, value []byte, headers map[string]string, partition int32) error {
+	if p.closed {
+		return errors.New("the producer has been closed")
+	}
+	message := newProducerMessage(topic, key, value, headers, partition)
+	message.Metadata.(*messageMetadata).enqueued = time.Now()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case p.producer.Input() <- message:
+		return nil
+	}
+}
+
+// Reports returns the delivery reports of the published messages.
+//
+// The channel will be closed once the producer has been closed and all the in-flight messages have been acknowledged.
+func (p *AsyncProducer) Reports() <-chan *DeliveryReport {
+	return p.reports
+}
+
+// Close flushes the buffered messages and closes the producer.
+func (p *AsyncProducer) Close() {
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.producer.AsyncClose()
+}
+
+func newDeliveryReport(message *sarama.ProducerMessage, err error) *DeliveryReport {
+	report := &DeliveryReport{
+		Err: err,
+	}
+	if message == nil {
+		return report
+	}
+	report.Partition = message.Partition
+	report.Offset = message.Offset
+	if metadata, ok := message.Metadata.(*messageMetadata); ok && !metadata.enqueued.IsZero() {
+		report.Latency = time.Since(metadata.enqueued)
+	}
+	return report
+}
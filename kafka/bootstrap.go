@@ -6,11 +6,25 @@ import (
 	"log"
 	"time"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 	"github.com/rcrowley/go-metrics"
 )
 
 func initClient(brokers []string, options ...Option) (sarama.Client, error) {
+	config, err := newConfig(brokers, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise the Kafka client: %w", err)
+	}
+
+	return client, nil
+}
+
+func newConfig(brokers []string, options ...Option) (*sarama.Config, error) {
 	if len(brokers) == 0 {
 		return nil, errors.New("the brokers list cannot be empty")
 	}
@@ -32,18 +46,25 @@ func initClient(brokers []string, options ...Option) (sarama.Client, error) {
 
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Return.Successes = true
-	config.Producer.Partitioner = sarama.NewHashPartitioner
+	config.Producer.Partitioner = newPartitioner
 	config.Consumer.MaxWaitTime = 500 * time.Millisecond
 
+	config.Consumer.IsolationLevel, err = toIsolationLevel(ops.isolation)
+	if err != nil {
+		return nil, err
+	}
+
+	if ops.producer != nil {
+		if err := ops.producer.apply(config); err != nil {
+			return nil, err
+		}
+	}
+
 	metrics.UseNilMetrics = true
 	if ops.sasl != nil {
-		config.Net.SASL.Enable = true
-		config.Net.SASL.Enable = true
-		config.Net.SASL.Version = ops.sasl.version
-		config.Net.SASL.Mechanism = ops.sasl.mechanism
-		config.Net.SASL.User = ops.sasl.username
-		config.Net.SASL.Password = ops.sasl.password
-		config.Net.SASL.SCRAMClientGeneratorFunc = ops.sasl.client
+		if err := ops.sasl.apply(config); err != nil {
+			return nil, err
+		}
 	}
 
 	if ops.TLS != nil {
@@ -51,10 +72,5 @@ func initClient(brokers []string, options ...Option) (sarama.Client, error) {
 		config.Net.TLS.Config = ops.TLS
 	}
 
-	client, err := sarama.NewClient(brokers, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialise the Kafka client: %w", err)
-	}
-
-	return client, nil
+	return config, nil
 }
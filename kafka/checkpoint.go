@@ -5,7 +5,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 	"github.com/araddon/dateparse"
 
 	"github.com/xitonix/trubka/internal"
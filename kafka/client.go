@@ -3,7 +3,7 @@ package kafka
 import (
 	"io"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 )
 
 type client interface {
@@ -13,3 +13,8 @@ type client interface {
 	GetOffset(topic string, partitionID int32, time int64) (int64, error)
 	io.Closer
 }
+
+type groupClient interface {
+	client
+	NewConsumerGroup(groupID string, initialOffset int64) (sarama.ConsumerGroup, error)
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// metadataClientMock serves the partition metadata of the topics.
+//
+// Calling any other method of the client will panic.
+type metadataClientMock struct {
+	sarama.Client
+	replicas map[string][][]int32
+}
+
+func (c *metadataClientMock) Partitions(topic string) ([]int32, error) {
+	assignments, ok := c.replicas[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	partitions := make([]int32, len(assignments))
+	for i := range assignments {
+		partitions[i] = int32(i)
+	}
+	return partitions, nil
+}
+
+func (c *metadataClientMock) Replicas(topic string, partition int32) ([]int32, error) {
+	assignments, ok := c.replicas[topic]
+	if !ok || int(partition) >= len(assignments) {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	return assignments[partition], nil
+}
+
+// clusterAdminMock serves the ongoing partition reassignments and records the submitted ones.
+//
+// Calling any other method of the cluster admin will panic.
+type clusterAdminMock struct {
+	sarama.ClusterAdmin
+	reassignments map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus
+	submitted     map[string][][]int32
+}
+
+func (a *clusterAdminMock) ListPartitionReassignments(topic string, partitions []int32) (map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus, error) {
+	result := make(map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus)
+	for _, partition := range partitions {
+		if status, ok := a.reassignments[topic][partition]; ok {
+			if result[topic] == nil {
+				result[topic] = make(map[int32]*sarama.PartitionReplicaReassignmentsStatus)
+			}
+			result[topic][partition] = status
+		}
+	}
+	return result, nil
+}
+
+func (a *clusterAdminMock) AlterPartitionReassignments(topic string, assignment [][]int32) error {
+	if a.submitted == nil {
+		a.submitted = make(map[string][][]int32)
+	}
+	if _, ok := a.submitted[topic]; ok {
+		return fmt.Errorf("the reassignments of %s topic have already been submitted", topic)
+	}
+	a.submitted[topic] = assignment
+	return nil
+}
@@ -0,0 +1,229 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+// HealthStatus the status of a cluster health check.
+type HealthStatus int
+
+const (
+	// HealthOK the check has passed.
+	HealthOK HealthStatus = iota
+	// HealthWarning issues have been found, but they are within the threshold.
+	HealthWarning
+	// HealthCritical the threshold has been exceeded.
+	HealthCritical
+)
+
+// String returns the string representation of the status.
+func (h HealthStatus) String() string {
+	switch h {
+	case HealthOK:
+		return "OK"
+	case HealthWarning:
+		return "WARNING"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// MarshalJSON marshals the status as a string.
+func (h HealthStatus) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + h.String() + `"`), nil
+}
+
+// HealthThresholds the maximum number of the issues which can be tolerated by the cluster health checks.
+type HealthThresholds struct {
+	// MaxUnderReplicated the maximum number of the partitions with out of sync replicas.
+	MaxUnderReplicated int
+	// MaxOfflineReplicas the maximum number of the partitions with offline replicas.
+	MaxOfflineReplicas int
+	// MaxLeaderless the maximum number of the partitions without a leader.
+	MaxLeaderless int
+	// MaxMissingBrokers the maximum number of the brokers which are missing from the cluster metadata.
+	MaxMissingBrokers int
+	// MinBrokers the minimum number of the brokers in the cluster metadata. Zero disables the check.
+	MinBrokers int
+}
+
+// PartitionHealth represents the replication status of an unhealthy partition.
+type PartitionHealth struct {
+	// Topic the topic name.
+	Topic string `json:"topic"`
+	// Partition the partition number.
+	Partition int32 `json:"partition"`
+	// Leader the ID of the leader broker, or -1 if the partition has no leader.
+	Leader int32 `json:"leader"`
+	// Replicas the IDs of the replication nodes.
+	Replicas []int32 `json:"replicas"`
+	// ISRs the IDs of the in-sync replicas.
+	ISRs []int32 `json:"in_sync_replicas"`
+	// OfflineReplicas the IDs of the offline replicas.
+	OfflineReplicas []int32 `json:"offline_replicas"`
+}
+
+// HealthCheck represents the result of a single cluster health check.
+type HealthCheck struct {
+	// Name the name of the check.
+	Name string `json:"name"`
+	// Value the number of the issues found by the check.
+	Value int `json:"value"`
+	// Threshold the human readable threshold of the check.
+	Threshold string `json:"threshold"`
+	// Status the status of the check.
+	Status HealthStatus `json:"status"`
+}
+
+// ClusterHealth the aggregated health status of the cluster.
+type ClusterHealth struct {
+	// Status the worst status of all the checks.
+	Status HealthStatus `json:"status"`
+	// Checks the results of the health checks.
+	Checks []*HealthCheck `json:"checks"`
+	// Brokers the brokers within the cluster metadata.
+	Brokers []*Broker `json:"brokers"`
+	// Controller the controller node, or nil if the cluster has no active controller.
+	Controller *Broker `json:"controller"`
+	// MissingBrokers the IDs of the brokers which have replicas assigned to them, but are missing from the metadata.
+	MissingBrokers []int32 `json:"missing_brokers"`
+	// Topics the number of the checked topics.
+	Topics int `json:"topics"`
+	// Partitions the number of the checked partitions.
+	Partitions int `json:"partitions"`
+	// UnderReplicated the partitions with out of sync replicas.
+	UnderReplicated []*PartitionHealth `json:"under_replicated"`
+	// OfflineReplicas the partitions with offline replicas.
+	OfflineReplicas []*PartitionHealth `json:"offline_replicas"`
+	// Leaderless the partitions without a leader.
+	Leaderless []*PartitionHealth `json:"leaderless"`
+}
+
+// Unhealthy returns the sorted list of all the partitions which have at least one issue.
+func (c *ClusterHealth) Unhealthy() []*PartitionHealth {
+	seen := make(map[*PartitionHealth]bool)
+	result := make([]*PartitionHealth, 0)
+	for _, partitions := range [][]*PartitionHealth{c.Leaderless, c.OfflineReplicas, c.UnderReplicated} {
+		for _, p := range partitions {
+			if !seen[p] {
+				seen[p] = true
+				result = append(result, p)
+			}
+		}
+	}
+	sortPartitionHealth(result)
+	return result
+}
+
+func newClusterHealth(brokers []*Broker, topics []*sarama.TopicMetadata, thresholds HealthThresholds) *ClusterHealth {
+	health := &ClusterHealth{
+		Brokers:         brokers,
+		MissingBrokers:  make([]int32, 0),
+		UnderReplicated: make([]*PartitionHealth, 0),
+		OfflineReplicas: make([]*PartitionHealth, 0),
+		Leaderless:      make([]*PartitionHealth, 0),
+	}
+	known := make(map[int32]bool)
+	for _, broker := range brokers {
+		known[broker.ID] = true
+		if broker.IsController {
+			health.Controller = broker
+		}
+	}
+
+	missing := make(map[int32]bool)
+	for _, topic := range topics {
+		health.Topics++
+		for _, pm := range topic.Partitions {
+			health.Partitions++
+			p := &PartitionHealth{
+				Topic:           topic.Name,
+				Partition:       pm.ID,
+				Leader:          pm.Leader,
+				Replicas:        pm.Replicas,
+				ISRs:            pm.Isr,
+				OfflineReplicas: pm.OfflineReplicas,
+			}
+			if pm.Leader < 0 || pm.Err == sarama.ErrLeaderNotAvailable {
+				health.Leaderless = append(health.Leaderless, p)
+			}
+			if len(pm.OfflineReplicas) > 0 {
+				health.OfflineReplicas = append(health.OfflineReplicas, p)
+			}
+			if len(pm.Isr) < len(pm.Replicas) {
+				health.UnderReplicated = append(health.UnderReplicated, p)
+			}
+			for _, id := range pm.Replicas {
+				if !known[id] {
+					missing[id] = true
+				}
+			}
+		}
+	}
+	for id := range missing {
+		health.MissingBrokers = append(health.MissingBrokers, id)
+	}
+	sort.Slice(health.MissingBrokers, func(i, j int) bool { return health.MissingBrokers[i] < health.MissingBrokers[j] })
+	sortPartitionHealth(health.UnderReplicated)
+	sortPartitionHealth(health.OfflineReplicas)
+	sortPartitionHealth(health.Leaderless)
+
+	controller := &HealthCheck{Name: "Active Controller", Threshold: "1", Value: 1}
+	if health.Controller == nil {
+		controller.Value = 0
+		controller.Status = HealthCritical
+	}
+	health.Checks = []*HealthCheck{
+		controller,
+		maxCheck("Under Replicated Partitions", len(health.UnderReplicated), thresholds.MaxUnderReplicated),
+		maxCheck("Offline Replica Partitions", len(health.OfflineReplicas), thresholds.MaxOfflineReplicas),
+		maxCheck("Leaderless Partitions", len(health.Leaderless), thresholds.MaxLeaderless),
+		maxCheck("Missing Brokers", len(health.MissingBrokers), thresholds.MaxMissingBrokers),
+	}
+	if thresholds.MinBrokers > 0 {
+		brokerCheck := &HealthCheck{
+			Name:      "Brokers",
+			Value:     len(brokers),
+			Threshold: fmt.Sprintf(">= %d", thresholds.MinBrokers),
+		}
+		if len(brokers) < thresholds.MinBrokers {
+			brokerCheck.Status = HealthCritical
+		}
+		health.Checks = append(health.Checks, brokerCheck)
+	}
+
+	for _, check := range health.Checks {
+		if check.Status > health.Status {
+			health.Status = check.Status
+		}
+	}
+	return health
+}
+
+// maxCheck reports the issues within the threshold as warnings, and the ones exceeding the threshold as critical.
+func maxCheck(name string, value, max int) *HealthCheck {
+	check := &HealthCheck{
+		Name:      name,
+		Value:     value,
+		Threshold: fmt.Sprintf("<= %d", max),
+	}
+	switch {
+	case value > max:
+		check.Status = HealthCritical
+	case value > 0:
+		check.Status = HealthWarning
+	}
+	return check
+}
+
+func sortPartitionHealth(partitions []*PartitionHealth) {
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].Topic != partitions[j].Topic {
+			return partitions[i].Topic < partitions[j].Topic
+		}
+		return partitions[i].Partition < partitions[j].Partition
+	})
+}
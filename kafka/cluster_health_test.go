@@ -0,0 +1,186 @@
+package kafka
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestNewClusterHealth(t *testing.T) {
+	brokers := []*Broker{
+		{ID: 1, Host: "broker-1", IsController: true},
+		{ID: 2, Host: "broker-2"},
+	}
+	healthy := []*sarama.TopicMetadata{
+		{
+			Name: "orders",
+			Partitions: []*sarama.PartitionMetadata{
+				{ID: 0, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}},
+				{ID: 1, Leader: 2, Replicas: []int32{2, 1}, Isr: []int32{2, 1}},
+			},
+		},
+	}
+	unhealthy := []*sarama.TopicMetadata{
+		{
+			Name: "orders",
+			Partitions: []*sarama.PartitionMetadata{
+				{ID: 1, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}},
+				{ID: 0, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}},
+			},
+		},
+		{
+			Name: "events",
+			Partitions: []*sarama.PartitionMetadata{
+				{ID: 0, Leader: -1, Replicas: []int32{3}, Isr: []int32{}, OfflineReplicas: []int32{3}, Err: sarama.ErrLeaderNotAvailable},
+			},
+		},
+	}
+
+	testCases := []struct {
+		title              string
+		brokers            []*Broker
+		topics             []*sarama.TopicMetadata
+		thresholds         HealthThresholds
+		expectedStatus     HealthStatus
+		expectedChecks     map[string]HealthStatus
+		expectedUnhealthy  []string
+		expectedMissing    []int32
+		expectedPartitions int
+	}{
+		{
+			title:              "healthy cluster",
+			brokers:            brokers,
+			topics:             healthy,
+			expectedStatus:     HealthOK,
+			expectedMissing:    []int32{},
+			expectedPartitions: 2,
+			expectedUnhealthy:  []string{},
+		},
+		{
+			title:          "no controller",
+			brokers:        []*Broker{{ID: 1, Host: "broker-1"}, {ID: 2, Host: "broker-2"}},
+			topics:         healthy,
+			expectedStatus: HealthCritical,
+			expectedChecks: map[string]HealthStatus{
+				"Active Controller": HealthCritical,
+			},
+			expectedMissing:    []int32{},
+			expectedPartitions: 2,
+			expectedUnhealthy:  []string{},
+		},
+		{
+			title:          "unhealthy partitions exceeding the thresholds",
+			brokers:        brokers,
+			topics:         unhealthy,
+			expectedStatus: HealthCritical,
+			expectedChecks: map[string]HealthStatus{
+				"Active Controller":           HealthOK,
+				"Under Replicated Partitions": HealthCritical,
+				"Offline Replica Partitions":  HealthCritical,
+				"Leaderless Partitions":       HealthCritical,
+				"Missing Brokers":             HealthCritical,
+			},
+			expectedMissing:    []int32{3},
+			expectedPartitions: 3,
+			expectedUnhealthy:  []string{"events/0", "orders/1"},
+		},
+		{
+			title:   "unhealthy partitions within the thresholds",
+			brokers: brokers,
+			topics:  unhealthy,
+			thresholds: HealthThresholds{
+				MaxUnderReplicated: 2,
+				MaxOfflineReplicas: 1,
+				MaxLeaderless:      1,
+				MaxMissingBrokers:  1,
+			},
+			expectedStatus: HealthWarning,
+			expectedChecks: map[string]HealthStatus{
+				"Under Replicated Partitions": HealthWarning,
+				"Offline Replica Partitions":  HealthWarning,
+				"Leaderless Partitions":       HealthWarning,
+				"Missing Brokers":             HealthWarning,
+			},
+			expectedMissing:    []int32{3},
+			expectedPartitions: 3,
+			expectedUnhealthy:  []string{"events/0", "orders/1"},
+		},
+		{
+			title:          "not enough brokers",
+			brokers:        brokers,
+			topics:         healthy,
+			thresholds:     HealthThresholds{MinBrokers: 3},
+			expectedStatus: HealthCritical,
+			expectedChecks: map[string]HealthStatus{
+				"Brokers": HealthCritical,
+			},
+			expectedMissing:    []int32{},
+			expectedPartitions: 2,
+			expectedUnhealthy:  []string{},
+		},
+		{
+			title:              "enough brokers",
+			brokers:            brokers,
+			topics:             healthy,
+			thresholds:         HealthThresholds{MinBrokers: 2},
+			expectedStatus:     HealthOK,
+			expectedChecks:     map[string]HealthStatus{"Brokers": HealthOK},
+			expectedMissing:    []int32{},
+			expectedPartitions: 2,
+			expectedUnhealthy:  []string{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual := newClusterHealth(tC.brokers, tC.topics, tC.thresholds)
+			if actual.Status != tC.expectedStatus {
+				t.Errorf("Expected status: %s, Actual: %s", tC.expectedStatus, actual.Status)
+			}
+			checks := make(map[string]HealthStatus)
+			for _, check := range actual.Checks {
+				checks[check.Name] = check.Status
+			}
+			for name, expected := range tC.expectedChecks {
+				status, ok := checks[name]
+				if !ok {
+					t.Errorf("Expected the %s check to be performed", name)
+					continue
+				}
+				if status != expected {
+					t.Errorf("%s: Expected status: %s, Actual: %s", name, expected, status)
+				}
+			}
+			if !reflect.DeepEqual(actual.MissingBrokers, tC.expectedMissing) {
+				t.Errorf("Expected missing brokers: %v, Actual: %v", tC.expectedMissing, actual.MissingBrokers)
+			}
+			if actual.Partitions != tC.expectedPartitions {
+				t.Errorf("Expected partitions: %d, Actual: %d", tC.expectedPartitions, actual.Partitions)
+			}
+			unhealthy := make([]string, 0)
+			for _, p := range actual.Unhealthy() {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s/%d", p.Topic, p.Partition))
+			}
+			if !reflect.DeepEqual(unhealthy, tC.expectedUnhealthy) {
+				t.Errorf("Expected unhealthy partitions: %v, Actual: %v", tC.expectedUnhealthy, unhealthy)
+			}
+		})
+	}
+}
+
+func TestHealthStatusMarshalJSON(t *testing.T) {
+	for status, expected := range map[HealthStatus]string{
+		HealthOK:       `"OK"`,
+		HealthWarning:  `"WARNING"`,
+		HealthCritical: `"CRITICAL"`,
+	} {
+		actual, err := status.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Failed to marshal %s: %s", status, err)
+		}
+		if string(actual) != expected {
+			t.Errorf("Expected: %s, Actual: %s", expected, actual)
+		}
+	}
+}
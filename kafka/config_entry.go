@@ -1,5 +1,11 @@
 package kafka
 
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
 // ConfigEntry represents a Kafka config.
 type ConfigEntry struct {
 	Name  string `json:"name"`
@@ -20,3 +26,45 @@ func (c ConfigEntriesByName) Swap(i, j int) {
 func (c ConfigEntriesByName) Less(i, j int) bool {
 	return c[i].Name < c[j].Name
 }
+
+// ConfigChange represents a change to a Kafka config.
+type ConfigChange struct {
+	Name     string `json:"name"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+	// Deleted is true if the config has been reverted to its default value.
+	Deleted bool `json:"deleted"`
+}
+
+// toAlterConfigEntries merges the requested changes with the current dynamic configurations of the resource.
+//
+// The result can be used in a (non-incremental) AlterConfigs request.
+func toAlterConfigEntries(resourceType sarama.ConfigResourceType, current []sarama.ConfigEntry, set map[string]string, remove []string) (map[string]*string, error) {
+	dynamicSource := sarama.SourceTopic
+	if resourceType == sarama.BrokerResource {
+		dynamicSource = sarama.SourceDynamicBroker
+	}
+	removed := make(map[string]bool)
+	for _, name := range remove {
+		removed[name] = true
+	}
+	result := make(map[string]*string)
+	for _, entry := range current {
+		if entry.Source != dynamicSource || removed[entry.Name] {
+			continue
+		}
+		if _, ok := set[entry.Name]; ok {
+			continue
+		}
+		if entry.Sensitive {
+			return nil, fmt.Errorf("the sensitive config %s cannot be preserved. Altering configurations with sensitive values requires Kafka 2.3.0 or newer", entry.Name)
+		}
+		value := entry.Value
+		result[entry.Name] = &value
+	}
+	for name, value := range set {
+		value := value
+		result[name] = &value
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestToAlterConfigEntries(t *testing.T) {
+	testCases := []struct {
+		title         string
+		resourceType  sarama.ConfigResourceType
+		current       []sarama.ConfigEntry
+		set           map[string]string
+		remove        []string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			title:        "dynamic topic configs are preserved",
+			resourceType: sarama.TopicResource,
+			current: []sarama.ConfigEntry{
+				{Name: "retention.ms", Value: "1000", Source: sarama.SourceTopic},
+				{Name: "cleanup.policy", Value: "delete", Source: sarama.SourceDefault},
+			},
+			set:      map[string]string{"segment.ms": "2000"},
+			expected: map[string]string{"retention.ms": "1000", "segment.ms": "2000"},
+		},
+		{
+			title:        "existing values are overridden",
+			resourceType: sarama.TopicResource,
+			current: []sarama.ConfigEntry{
+				{Name: "retention.ms", Value: "1000", Source: sarama.SourceTopic},
+			},
+			set:      map[string]string{"retention.ms": "2000"},
+			expected: map[string]string{"retention.ms": "2000"},
+		},
+		{
+			title:        "deleted configs are removed",
+			resourceType: sarama.TopicResource,
+			current: []sarama.ConfigEntry{
+				{Name: "retention.ms", Value: "1000", Source: sarama.SourceTopic},
+				{Name: "segment.ms", Value: "2000", Source: sarama.SourceTopic},
+			},
+			remove:   []string{"retention.ms"},
+			expected: map[string]string{"segment.ms": "2000"},
+		},
+		{
+			title:        "dynamic broker configs are preserved",
+			resourceType: sarama.BrokerResource,
+			current: []sarama.ConfigEntry{
+				{Name: "log.cleaner.threads", Value: "2", Source: sarama.SourceDynamicBroker},
+				{Name: "log.retention.ms", Value: "1000", Source: sarama.SourceStaticBroker},
+			},
+			set:      map[string]string{"log.cleaner.backoff.ms": "100"},
+			expected: map[string]string{"log.cleaner.threads": "2", "log.cleaner.backoff.ms": "100"},
+		},
+		{
+			title:        "sensitive dynamic configs cannot be preserved",
+			resourceType: sarama.BrokerResource,
+			current: []sarama.ConfigEntry{
+				{Name: "ssl.key.password", Sensitive: true, Source: sarama.SourceDynamicBroker},
+			},
+			set:           map[string]string{"log.cleaner.threads": "2"},
+			expectedError: "the sensitive config ssl.key.password cannot be preserved",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := toAlterConfigEntries(tC.resourceType, tC.current, tC.set, tC.remove)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(actual) != len(tC.expected) {
+				t.Fatalf("Expected %d entries, Actual: %d", len(tC.expected), len(actual))
+			}
+			for name, value := range tC.expected {
+				if v, ok := actual[name]; !ok || *v != value {
+					t.Errorf("Expected %s=%s, Actual: %v", name, value, v)
+				}
+			}
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 
 	"github.com/xitonix/trubka/internal"
 )
@@ -26,6 +26,9 @@ type Consumer struct {
 	exclusive               bool
 	idleTimeout             time.Duration
 	wg                      sync.WaitGroup
+	groupID                 string
+	groupClient             groupClient
+	groupHandler            *groupHandler
 }
 
 // NewConsumer creates a new instance of Kafka cluster consumer.
@@ -49,6 +52,29 @@ func NewConsumer(
 	}
 }
 
+// NewGroupConsumer creates a new instance of Kafka cluster consumer which joins the specified consumer group.
+//
+// In consumer group mode, the partitions are assigned to the consumer by the group coordinator and
+// the offsets of the processed messages are committed to the brokers instead of the local offset store.
+func NewGroupConsumer(
+	groupID string,
+	client groupClient,
+	printer internal.Printer,
+	enableAutoTopicCreation bool) *Consumer {
+
+	events := make(chan *Event, 128)
+	return &Consumer{
+		printer:                 printer,
+		client:                  client,
+		groupClient:             client,
+		groupHandler:            newGroupHandler(events, printer),
+		groupID:                 groupID,
+		enableAutoTopicCreation: enableAutoTopicCreation,
+		events:                  events,
+		localOffsets:            make(TopicPartitionOffset),
+	}
+}
+
 // GetTopics fetches the topics from the server.
 func (c *Consumer) GetTopics(search *regexp.Regexp) ([]string, error) {
 	if c.remoteTopics != nil {
@@ -90,6 +116,10 @@ func (c *Consumer) Start(ctx context.Context, topics map[string]*PartitionCheckp
 		return errors.New("the topic list cannot be empty")
 	}
 
+	if c.isGroupMember() {
+		return c.consumeGroup(ctx, topics)
+	}
+
 	go func() {
 		for err := range c.store.errors() {
 			c.printer.Errorf(internal.Forced, "Offset Storage Error: %s", err)
@@ -108,7 +138,13 @@ func (c *Consumer) Start(ctx context.Context, topics map[string]*PartitionCheckp
 }
 
 // StoreOffset stores the offset of the successfully processed message into the offset store.
+//
+// In consumer group mode, the offset will be committed to the brokers.
 func (c *Consumer) StoreOffset(event *Event) {
+	if c.isGroupMember() {
+		c.groupHandler.commit(event.Topic, event.Partition, event.Offset+1)
+		return
+	}
 	err := c.store.commit(event.Topic, event.Partition, event.Offset+1)
 	if err != nil {
 		c.printer.Errorf(internal.Forced, "Failed to commit the offset: %s.", err)
@@ -119,6 +155,9 @@ func (c *Consumer) StoreOffset(event *Event) {
 //
 // Make sure you call this function once you processed all the messages.
 func (c *Consumer) CloseOffsetStore() {
+	if c.store == nil {
+		return
+	}
 	c.store.close()
 }
 
@@ -136,6 +175,65 @@ func (c *Consumer) Close() {
 	})
 }
 
+func (c *Consumer) isGroupMember() bool {
+	return c.groupClient != nil
+}
+
+func (c *Consumer) consumeGroup(ctx context.Context, topics map[string]*PartitionCheckpoints) error {
+	if err := c.checkTopicsExist(topics); err != nil {
+		return err
+	}
+
+	var initialOffset int64
+	topicList := make([]string, 0, len(topics))
+	for topic, checkpoints := range topics {
+		offset, err := checkpoints.groupInitialOffset()
+		if err != nil {
+			return err
+		}
+		if len(topicList) > 0 && offset != initialOffset {
+			return errors.New("all the topics must have the same start checkpoint in consumer group mode")
+		}
+		initialOffset = offset
+		topicList = append(topicList, topic)
+	}
+
+	c.printer.Infof(internal.VeryVerbose, "Joining %s consumer group.", c.groupID)
+	group, err := c.groupClient.NewConsumerGroup(c.groupID, initialOffset)
+	if err != nil {
+		return fmt.Errorf("failed to join %s consumer group: %w", c.groupID, err)
+	}
+
+	go func() {
+		for err := range group.Errors() {
+			c.printer.Errorf(internal.Forced, "Consumer Group Error: %s", err)
+		}
+	}()
+
+	defer func() {
+		c.printer.Infof(internal.VeryVerbose, "Leaving %s consumer group.", c.groupID)
+		if err := group.Close(); err != nil {
+			c.printer.Errorf(internal.Forced, "Failed to close the consumer group: %s.", err)
+		}
+		c.Close()
+	}()
+
+	for {
+		// Consume needs to be called in a loop. It returns when a server-side rebalance happens,
+		// so that the consumer session can be recreated to get the new claims.
+		err = group.Consume(ctx, topicList, c.groupHandler)
+		if err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			return fmt.Errorf("failed to consume from %s consumer group: %w", c.groupID, err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
 func (c *Consumer) consumeTopics(ctx context.Context, topicPartitionOffsets TopicPartitionOffset) {
 	parentCtx, cancelAllPartitionConsumers := context.WithCancel(ctx)
 	defer cancelAllPartitionConsumers()
@@ -210,6 +308,7 @@ func (c *Consumer) consumePartition(ctx context.Context, topic string, partition
 		return m.Offset > offset.stopAt.offset
 	}
 
+	tracker := newOffsetTracker(offset.Current)
 	lastMessages := make(chan time.Time, 10)
 	forceClose := make(chan interface{})
 	if c.idleTimeout > 0 {
@@ -243,6 +342,8 @@ func (c *Consumer) consumePartition(ctx context.Context, topic string, partition
 				Timestamp: m.Timestamp,
 				Partition: m.Partition,
 				Offset:    m.Offset,
+				Headers:   m.Headers,
+				Skipped:   tracker.skipped(m.Offset),
 			}
 
 		case err, more := <-pc.Errors():
@@ -277,28 +378,36 @@ func (c *Consumer) monitorMessageArrival(lastMessages chan time.Time, forceClose
 	}
 }
 
-func (c *Consumer) fetchTopicPartitions(topics map[string]*PartitionCheckpoints) (TopicPartitionOffset, error) {
+func (c *Consumer) checkTopicsExist(topics map[string]*PartitionCheckpoints) error {
+	if c.enableAutoTopicCreation {
+		return nil
+	}
+	// We need to check if the requested topic(s) exist on the server
+	// That's why we need to get the list of the existing topics from the brokers.
+	remote, err := c.GetTopics(nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the topic list from the broker(s): %w", err)
+	}
 	existing := make(map[string]interface{})
-	if !c.enableAutoTopicCreation {
-		// We need to check if the requested topic(s) exist on the server
-		// That's why we need to get the list of the existing topics from the brokers.
-		remote, err := c.GetTopics(nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch the topic list from the broker(s): %w", err)
-		}
-		for _, t := range remote {
-			existing[t] = nil
+	for _, t := range remote {
+		existing[t] = nil
+	}
+	for topic := range topics {
+		if _, ok := existing[topic]; !ok {
+			return fmt.Errorf("failed to find the topic %s on the server. You must create the topic manually or enable automatic topic creation both on the server and in trubka", topic)
 		}
 	}
+	return nil
+}
+
+func (c *Consumer) fetchTopicPartitions(topics map[string]*PartitionCheckpoints) (TopicPartitionOffset, error) {
+	if err := c.checkTopicsExist(topics); err != nil {
+		return nil, err
+	}
 
 	topicPartitionOffsets := make(TopicPartitionOffset)
 
 	for topic, checkpoints := range topics {
-		if !c.enableAutoTopicCreation {
-			if _, ok := existing[topic]; !ok {
-				return nil, fmt.Errorf("failed to find the topic %s on the server. You must create the topic manually or enable automatic topic creation both on the server and in trubka", topic)
-			}
-		}
 		offsets, err := c.calculateStartingOffsets(topic, checkpoints)
 		if err != nil {
 			return nil, err
@@ -3,7 +3,7 @@ package kafka
 import (
 	"strings"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 )
 
 // GroupMembers represents a map to hold consumer group members.
@@ -3,21 +3,27 @@ package kafka
 import (
 	"fmt"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 )
 
 // ConsumerWrapper wraps Sarama consumer and its underlying client.
 type ConsumerWrapper struct {
 	sarama.Consumer
-	client sarama.Client
+	client  sarama.Client
+	brokers []string
+	config  *sarama.Config
 }
 
 // NewConsumerWrapper creates a new instance of consumer wrapper.
 func NewConsumerWrapper(brokers []string, options ...Option) (*ConsumerWrapper, error) {
-	client, err := initClient(brokers, options...)
+	config, err := newConfig(brokers, options...)
 	if err != nil {
 		return nil, err
 	}
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise the Kafka client: %w", err)
+	}
 	consumer, err := sarama.NewConsumerFromClient(client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise the Kafka consumer: %w", err)
@@ -26,6 +32,8 @@ func NewConsumerWrapper(brokers []string, options ...Option) (*ConsumerWrapper,
 	return &ConsumerWrapper{
 		Consumer: consumer,
 		client:   client,
+		brokers:  brokers,
+		config:   config,
 	}, nil
 }
 
@@ -34,3 +42,17 @@ func NewConsumerWrapper(brokers []string, options ...Option) (*ConsumerWrapper,
 func (c *ConsumerWrapper) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
 	return c.client.GetOffset(topic, partitionID, time)
 }
+
+// NewConsumerGroup creates a new consumer group with the same settings as the underlying client.
+//
+// The initial offset will be used if no offset has been committed for the group yet.
+// The consumer group owns a dedicated client, so that the configuration of the underlying client remains intact.
+func (c *ConsumerWrapper) NewConsumerGroup(groupID string, initialOffset int64) (sarama.ConsumerGroup, error) {
+	config := *c.config
+	config.Consumer.Offsets.Initial = initialOffset
+	group, err := sarama.NewConsumerGroup(c.brokers, groupID, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise the consumer group: %w", err)
+	}
+	return group, nil
+}
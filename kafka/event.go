@@ -1,6 +1,10 @@
 package kafka
 
-import "time"
+import (
+	"time"
+
+	"github.com/IBM/sarama"
+)
 
 // Event Kafka event.
 type Event struct {
@@ -16,4 +20,9 @@ type Event struct {
 	Partition int32
 	// Offset the message offset.
 	Offset int64
+	// Headers the message headers.
+	Headers []*sarama.RecordHeader
+	// Skipped the number of offsets skipped since the previous message of the partition (eg. the transaction markers,
+	// the messages of the aborted transactions in read_committed mode or the compacted records).
+	Skipped int64
 }
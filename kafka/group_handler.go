@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/xitonix/trubka/internal"
+)
+
+// groupHandler is an implementation of sarama.ConsumerGroupHandler to consume the partitions claimed by a consumer group member.
+type groupHandler struct {
+	mux     sync.RWMutex
+	session sarama.ConsumerGroupSession
+	events  chan<- *Event
+	printer internal.Printer
+}
+
+func newGroupHandler(events chan<- *Event, printer internal.Printer) *groupHandler {
+	return &groupHandler{
+		events:  events,
+		printer: printer,
+	}
+}
+
+// Setup is run at the beginning of a new session, before ConsumeClaim.
+func (g *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.session = session
+	g.printer.Infof(internal.Verbose, "Joined the consumer group (Member ID: %s, Generation: %d).", session.MemberID(), session.GenerationID())
+	for topic, partitions := range session.Claims() {
+		g.printer.Infof(internal.VeryVerbose, "Topic: %s, Claimed Partitions: %s", topic, TopicPartitions{topic: partitions}.SortedPartitionsString(topic))
+	}
+	return nil
+}
+
+// Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited.
+func (g *groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.session = nil
+	g.printer.Infof(internal.Verbose, "The consumer group session has been closed (Member ID: %s, Generation: %d).", session.MemberID(), session.GenerationID())
+	return nil
+}
+
+// ConsumeClaim publishes the messages of the claimed partition to the events channel until the session is over.
+func (g *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	g.printer.Infof(internal.VeryVerbose, "Consuming from Topic: %s, Partition: %d, Offset: %v", claim.Topic(), claim.Partition(), getOffsetString(claim.InitialOffset()))
+	tracker := newOffsetTracker(claim.InitialOffset())
+	for {
+		select {
+		case <-session.Context().Done():
+			g.printer.Infof(internal.VeryVerbose, "Releasing Topic: %s, Partition %d", claim.Topic(), claim.Partition())
+			return nil
+		case m, more := <-claim.Messages():
+			if !more {
+				return nil
+			}
+			event := &Event{
+				Topic:     m.Topic,
+				Key:       m.Key,
+				Value:     m.Value,
+				Timestamp: m.Timestamp,
+				Partition: m.Partition,
+				Offset:    m.Offset,
+				Headers:   m.Headers,
+				Skipped:   tracker.skipped(m.Offset),
+			}
+			// The partition may get revoked while waiting for the event to be picked up.
+			// The message must not be handed out in that case, because its offset can no longer be committed by this session.
+			select {
+			case <-session.Context().Done():
+				g.printer.Infof(internal.VeryVerbose, "Releasing Topic: %s, Partition %d", claim.Topic(), claim.Partition())
+				return nil
+			case g.events <- event:
+			}
+		}
+	}
+}
+
+// commit marks the offset of the processed message to be committed to the brokers.
+//
+// The marked offsets will be periodically committed to the server by the group session.
+func (g *groupHandler) commit(topic string, partition int32, offset int64) {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	if g.session == nil {
+		return
+	}
+	g.session.MarkOffset(topic, partition, offset, "")
+}
@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupHandlerConsumeClaim(t *testing.T) {
+	testCases := []struct {
+		title          string
+		messages       int
+		revoke         bool
+		expectedEvents int
+	}{
+		{
+			title:          "no messages",
+			expectedEvents: 0,
+		},
+		{
+			title:          "all the messages are delivered",
+			messages:       10,
+			expectedEvents: 10,
+		},
+		{
+			title:          "revoked partition while waiting for the events to be picked up",
+			messages:       10,
+			revoke:         true,
+			expectedEvents: 0,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			events := make(chan *Event)
+			handler := newGroupHandler(events, &printerMock{})
+			session := newGroupSessionMock(map[string][]int32{"topic": {0}})
+			// The messages channel is only closed when the partition is not going to be revoked,
+			// so that the session context is the only way out of the revoked claim.
+			claim := newGroupClaimMock("topic", 0, tC.messages, !tC.revoke)
+
+			if tC.revoke {
+				session.revoke()
+			}
+
+			done := make(chan error)
+			go func() {
+				done <- handler.ConsumeClaim(session, claim)
+			}()
+
+			// Nobody picks up the events of a revoked partition.
+			var picker <-chan *Event = events
+			if tC.revoke {
+				picker = nil
+			}
+
+			var received int
+			for {
+				select {
+				case event := <-picker:
+					if event.Offset != int64(received) {
+						t.Errorf("Expected offset: %d, Actual: %d", received, event.Offset)
+					}
+					received++
+					continue
+				case err := <-done:
+					if err != nil {
+						t.Errorf("Did not expect an error, but received: '%v'", err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("The claim has not been released")
+				}
+				break
+			}
+
+			if received != tC.expectedEvents {
+				t.Errorf("Expected events: %d, Actual: %d", tC.expectedEvents, received)
+			}
+		})
+	}
+}
+
+func TestGroupHandlerCommit(t *testing.T) {
+	testCases := []struct {
+		title         string
+		closedSession bool
+		expectMarked  bool
+	}{
+		{
+			title:        "active session",
+			expectMarked: true,
+		},
+		{
+			title:         "closed session",
+			closedSession: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			handler := newGroupHandler(make(chan *Event), &printerMock{})
+			session := newGroupSessionMock(map[string][]int32{"topic": {0}})
+			if err := handler.Setup(session); err != nil {
+				t.Fatalf("Did not expect an error, but received: '%v'", err)
+			}
+			if tC.closedSession {
+				if err := handler.Cleanup(session); err != nil {
+					t.Fatalf("Did not expect an error, but received: '%v'", err)
+				}
+			}
+
+			handler.commit("topic", 0, 10)
+
+			offset, marked := session.markedOffsets()["topic"][0]
+			if marked != tC.expectMarked {
+				t.Fatalf("Expected marked: %v, Actual: %v", tC.expectMarked, marked)
+			}
+			if marked && offset.Current != 10 {
+				t.Errorf("Expected offset: 10, Actual: %d", offset.Current)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+// PartitionOffsetReset represents the offset reset plan of a consumer group partition.
+type PartitionOffsetReset struct {
+	// Partition the partition number.
+	Partition int32
+	// Earliest the earliest available offset of the partition.
+	Earliest int64
+	// Latest the latest available offset of the partition.
+	Latest int64
+	// Current the offset committed by the group. It will be set to -1 if the group has not committed any offsets for the partition.
+	Current int64
+	// Target the new offset of the partition.
+	Target int64
+}
+
+// GroupOffsetsReset represents the offset reset plan of a consumer group for all the requested topics.
+type GroupOffsetsReset map[string][]PartitionOffsetReset
+
+// ToJSON returns an object ready to be serialised into json string.
+func (g GroupOffsetsReset) ToJSON() interface{} {
+	if g == nil {
+		return nil
+	}
+	type partition struct {
+		Partition int32  `json:"partition"`
+		Earliest  int64  `json:"earliest_offset"`
+		Latest    int64  `json:"latest_offset"`
+		Current   *int64 `json:"current_offset"`
+		Target    int64  `json:"new_offset"`
+	}
+	type topic struct {
+		Topic      string      `json:"topic"`
+		Partitions []partition `json:"partitions"`
+	}
+	output := make([]topic, 0, len(g))
+	for _, name := range g.SortedTopics() {
+		t := topic{
+			Topic:      name,
+			Partitions: make([]partition, len(g[name])),
+		}
+		for i, reset := range g[name] {
+			p := partition{
+				Partition: reset.Partition,
+				Earliest:  reset.Earliest,
+				Latest:    reset.Latest,
+				Target:    reset.Target,
+			}
+			if reset.Current >= 0 {
+				current := reset.Current
+				p.Current = &current
+			}
+			t.Partitions[i] = p
+		}
+		output = append(output, t)
+	}
+	return output
+}
+
+// SortedTopics returns a list of sorted topics.
+func (g GroupOffsetsReset) SortedTopics() []string {
+	sorted := make([]string, 0, len(g))
+	for topic := range g {
+		sorted = append(sorted, topic)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// resetOffset calculates the target offset of the partition based on the checkpoint.
+//
+// The offsetByTime function will be called to find the offset of the first message at or after the timestamp of a time based checkpoint.
+// The result will always be within the earliest and latest offsets of the partition.
+func (c *checkpoint) resetOffset(earliest, latest int64, offsetByTime func(millis int64) (int64, error)) (int64, error) {
+	var offset int64
+	switch c.mode {
+	case predefinedMode:
+		offset = latest
+		if c.offset == sarama.OffsetOldest {
+			offset = earliest
+		}
+	case explicitMode:
+		offset = c.offset
+	case timestampMode:
+		if c.offset == sarama.OffsetOldest {
+			return earliest, nil
+		}
+		var err error
+		offset, err = offsetByTime(c.offset)
+		if err != nil {
+			return 0, err
+		}
+		if offset < 0 {
+			// There is no message at or after the requested time.
+			offset = latest
+		}
+	default:
+		return 0, errors.New("local offsets are not acceptable target offsets")
+	}
+	return clampOffset(offset, earliest, latest), nil
+}
+
+// shiftOffset moves the current offset by the specified number of messages, within the earliest and latest offsets of the partition.
+func shiftOffset(current, shiftBy, earliest, latest int64) (int64, error) {
+	if current < 0 {
+		return 0, errors.New("the group has no committed offset to shift")
+	}
+	return clampOffset(current+shiftBy, earliest, latest), nil
+}
+
+func clampOffset(offset, earliest, latest int64) int64 {
+	if offset < earliest {
+		return earliest
+	}
+	if offset > latest {
+		return latest
+	}
+	return offset
+}
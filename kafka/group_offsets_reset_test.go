@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckpointResetOffset(t *testing.T) {
+	const (
+		earliest int64 = 100
+		latest   int64 = 200
+	)
+	at := time.Date(2020, 7, 15, 14, 24, 23, 0, time.UTC)
+	testCases := []struct {
+		title           string
+		checkpoint      *checkpoint
+		offsetByTime    int64
+		offsetByTimeErr error
+		expected        int64
+		expectedError   string
+	}{
+		{
+			title:      "oldest",
+			checkpoint: newPredefinedCheckpoint(true),
+			expected:   earliest,
+		},
+		{
+			title:      "newest",
+			checkpoint: newPredefinedCheckpoint(false),
+			expected:   latest,
+		},
+		{
+			title:      "explicit offset within range",
+			checkpoint: newExplicitCheckpoint(150),
+			expected:   150,
+		},
+		{
+			title:      "explicit offset before the earliest offset",
+			checkpoint: newExplicitCheckpoint(10),
+			expected:   earliest,
+		},
+		{
+			title:      "explicit offset after the latest offset",
+			checkpoint: newExplicitCheckpoint(1000),
+			expected:   latest,
+		},
+		{
+			title:        "timestamp",
+			checkpoint:   newTimeCheckpoint(at),
+			offsetByTime: 120,
+			expected:     120,
+		},
+		{
+			title:        "timestamp with no message after the requested time",
+			checkpoint:   newTimeCheckpoint(at),
+			offsetByTime: -1,
+			expected:     latest,
+		},
+		{
+			title:      "zero timestamp",
+			checkpoint: newTimeCheckpoint(time.Time{}),
+			expected:   earliest,
+		},
+		{
+			title:           "timestamp lookup failure",
+			checkpoint:      newTimeCheckpoint(at),
+			offsetByTimeErr: errors.New("lookup failed"),
+			expectedError:   "lookup failed",
+		},
+		{
+			title:         "local checkpoint",
+			checkpoint:    newLocalCheckpoint(),
+			expectedError: "local offsets are not acceptable target offsets",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := tC.checkpoint.resetOffset(earliest, latest, func(millis int64) (int64, error) {
+				if millis != at.UnixNano()/int64(time.Millisecond) {
+					t.Errorf("Expected timestamp: %d, Actual: %d", at.UnixNano()/int64(time.Millisecond), millis)
+				}
+				return tC.offsetByTime, tC.offsetByTimeErr
+			})
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err == nil && actual != tC.expected {
+				t.Errorf("Expected offset: %d, Actual: %d", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestShiftOffset(t *testing.T) {
+	testCases := []struct {
+		title         string
+		current       int64
+		shiftBy       int64
+		expected      int64
+		expectedError string
+	}{
+		{
+			title:    "shift forward",
+			current:  150,
+			shiftBy:  10,
+			expected: 160,
+		},
+		{
+			title:    "shift backward",
+			current:  150,
+			shiftBy:  -10,
+			expected: 140,
+		},
+		{
+			title:    "shift before the earliest offset",
+			current:  150,
+			shiftBy:  -100,
+			expected: 100,
+		},
+		{
+			title:    "shift after the latest offset",
+			current:  150,
+			shiftBy:  100,
+			expected: 200,
+		},
+		{
+			title:         "no committed offset",
+			current:       -1,
+			shiftBy:       10,
+			expectedError: "the group has no committed offset to shift",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := shiftOffset(tC.current, tC.shiftBy, 100, 200)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err == nil && actual != tC.expected {
+				t.Errorf("Expected offset: %d, Actual: %d", tC.expected, actual)
+			}
+		})
+	}
+}
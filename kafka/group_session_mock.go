@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBM/sarama"
+)
+
+type groupSessionMock struct {
+	mux    sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	claims map[string][]int32
+	marked TopicPartitionOffset
+}
+
+func newGroupSessionMock(claims map[string][]int32) *groupSessionMock {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &groupSessionMock{
+		ctx:    ctx,
+		cancel: cancel,
+		claims: claims,
+		marked: make(TopicPartitionOffset),
+	}
+}
+
+func (g *groupSessionMock) Claims() map[string][]int32 {
+	return g.claims
+}
+
+func (g *groupSessionMock) MemberID() string {
+	return "member"
+}
+
+func (g *groupSessionMock) GenerationID() int32 {
+	return 1
+}
+
+func (g *groupSessionMock) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if _, ok := g.marked[topic]; !ok {
+		g.marked[topic] = make(PartitionOffset)
+	}
+	g.marked[topic][partition] = Offset{Current: offset}
+}
+
+func (g *groupSessionMock) Commit() {
+}
+
+func (g *groupSessionMock) ResetOffset(_ string, _ int32, _ int64, _ string) {
+}
+
+func (g *groupSessionMock) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	g.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (g *groupSessionMock) Context() context.Context {
+	return g.ctx
+}
+
+func (g *groupSessionMock) revoke() {
+	g.cancel()
+}
+
+func (g *groupSessionMock) markedOffsets() TopicPartitionOffset {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.marked
+}
+
+type groupClaimMock struct {
+	topic     string
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func newGroupClaimMock(topic string, partition int32, numberOfMessages int, closed bool) *groupClaimMock {
+	messages := make(chan *sarama.ConsumerMessage, numberOfMessages)
+	for i := 0; i < numberOfMessages; i++ {
+		messages <- &sarama.ConsumerMessage{
+			Topic:     topic,
+			Partition: partition,
+			Offset:    int64(i),
+		}
+	}
+	if closed {
+		close(messages)
+	}
+	return &groupClaimMock{
+		topic:     topic,
+		partition: partition,
+		messages:  messages,
+	}
+}
+
+func (g *groupClaimMock) Topic() string {
+	return g.topic
+}
+
+func (g *groupClaimMock) Partition() int32 {
+	return g.partition
+}
+
+func (g *groupClaimMock) InitialOffset() int64 {
+	return 0
+}
+
+func (g *groupClaimMock) HighWaterMarkOffset() int64 {
+	return int64(cap(g.messages))
+}
+
+func (g *groupClaimMock) Messages() <-chan *sarama.ConsumerMessage {
+	return g.messages
+}
@@ -0,0 +1,28 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	// ReadUncommitted consumes all the messages, including the messages of the aborted and ongoing transactions.
+	ReadUncommitted = "read_uncommitted"
+	// ReadCommitted only consumes the non-transactional messages and the messages of the committed transactions.
+	ReadCommitted = "read_committed"
+)
+
+// IsolationLevels the supported consumer isolation levels.
+var IsolationLevels = []string{ReadUncommitted, ReadCommitted}
+
+func toIsolationLevel(level string) (sarama.IsolationLevel, error) {
+	switch level {
+	case "", ReadUncommitted:
+		return sarama.ReadUncommitted, nil
+	case ReadCommitted:
+		return sarama.ReadCommitted, nil
+	default:
+		return sarama.ReadUncommitted, fmt.Errorf("invalid isolation level %q. The isolation level must be %s or %s", level, ReadUncommitted, ReadCommitted)
+	}
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestToIsolationLevel(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         string
+		expected      sarama.IsolationLevel
+		expectedError string
+	}{
+		{
+			title:    "empty input",
+			expected: sarama.ReadUncommitted,
+		},
+		{
+			title:    "read uncommitted",
+			input:    ReadUncommitted,
+			expected: sarama.ReadUncommitted,
+		},
+		{
+			title:    "read committed",
+			input:    ReadCommitted,
+			expected: sarama.ReadCommitted,
+		},
+		{
+			title:         "invalid isolation level",
+			input:         "serializable",
+			expectedError: `invalid isolation level "serializable". The isolation level must be read_uncommitted or read_committed`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := toIsolationLevel(tC.input)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err == nil && actual != tC.expected {
+				t.Errorf("Expected isolation level: %v, Actual: %v", tC.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	// PreferredElection elects the preferred replica (the first replica in the assignment) as the leader.
+	PreferredElection = "preferred"
+	// UncleanElection elects an out of sync replica as the leader, if none of the in-sync replicas are available.
+	UncleanElection = "unclean"
+)
+
+// LeaderElection represents the leader election result of a topic partition.
+type LeaderElection struct {
+	// Topic the topic name.
+	Topic string `json:"topic"`
+	// Partition the partition number.
+	Partition int32 `json:"partition"`
+	// Elected is true if a new leader has been elected for the partition.
+	Elected bool `json:"elected"`
+	// Error the reason of the failure, if the leader could not be elected.
+	Error string `json:"error,omitempty"`
+}
+
+// Status returns the human readable election status of the partition.
+func (l *LeaderElection) Status() string {
+	if l.Elected {
+		return "Elected"
+	}
+	if l.Error == "" {
+		return "Not Needed"
+	}
+	return "Failed"
+}
+
+func toElectionType(electionType string) (sarama.ElectionType, error) {
+	switch strings.ToLower(strings.TrimSpace(electionType)) {
+	case PreferredElection:
+		return sarama.PreferredElection, nil
+	case UncleanElection:
+		return sarama.UncleanElection, nil
+	default:
+		return 0, fmt.Errorf("invalid election type %q. The election type must be either %s or %s", electionType, PreferredElection, UncleanElection)
+	}
+}
+
+func fromElectionResults(results map[string]map[int32]*sarama.PartitionResult) []*LeaderElection {
+	elections := make([]*LeaderElection, 0)
+	for topic, partitions := range results {
+		for partition, result := range partitions {
+			election := &LeaderElection{
+				Topic:     topic,
+				Partition: partition,
+			}
+			switch {
+			case result == nil || errors.Is(result.ErrorCode, sarama.ErrNoError):
+				election.Elected = true
+			case errors.Is(result.ErrorCode, sarama.ErrElectionNotNeeded):
+			default:
+				election.Error = result.ErrorCode.Error()
+				if result.ErrorMessage != nil && *result.ErrorMessage != "" {
+					election.Error = *result.ErrorMessage
+				}
+			}
+			elections = append(elections, election)
+		}
+	}
+	sort.Slice(elections, func(i, j int) bool {
+		if elections[i].Topic != elections[j].Topic {
+			return elections[i].Topic < elections[j].Topic
+		}
+		return elections[i].Partition < elections[j].Partition
+	})
+	return elections
+}
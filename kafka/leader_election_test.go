@@ -0,0 +1,75 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestToElectionType(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         string
+		expected      sarama.ElectionType
+		expectedError string
+	}{
+		{
+			title:    "preferred",
+			input:    "preferred",
+			expected: sarama.PreferredElection,
+		},
+		{
+			title:    "unclean with mixed case and spaces",
+			input:    " Unclean ",
+			expected: sarama.UncleanElection,
+		},
+		{
+			title:         "invalid type",
+			input:         "random",
+			expectedError: `invalid election type "random". The election type must be either preferred or unclean`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := toElectionType(tC.input)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err == nil && actual != tC.expected {
+				t.Errorf("Expected election type: %v, Actual: %v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFromElectionResults(t *testing.T) {
+	message := "custom error"
+	empty := ""
+	results := map[string]map[int32]*sarama.PartitionResult{
+		"orders": {
+			1: {ErrorCode: sarama.ErrElectionNotNeeded},
+			0: {ErrorCode: sarama.ErrNoError},
+		},
+		"events": {
+			2: {ErrorCode: sarama.ErrPreferredLeaderNotAvailable, ErrorMessage: &message},
+			0: {ErrorCode: sarama.ErrUnknownTopicOrPartition, ErrorMessage: &empty},
+		},
+	}
+	expected := []*LeaderElection{
+		{Topic: "events", Partition: 0, Error: sarama.ErrUnknownTopicOrPartition.Error()},
+		{Topic: "events", Partition: 2, Error: message},
+		{Topic: "orders", Partition: 0, Elected: true},
+		{Topic: "orders", Partition: 1},
+	}
+	actual := fromElectionResults(results)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected: %+v, Actual: %+v", expected, actual)
+	}
+	expectedStatuses := []string{"Failed", "Failed", "Elected", "Not Needed"}
+	for i, election := range actual {
+		if election.Status() != expectedStatuses[i] {
+			t.Errorf("Expected status of %s/%d: %s, Actual: %s", election.Topic, election.Partition, expectedStatuses[i], election.Status())
+		}
+	}
+}
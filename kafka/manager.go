@@ -8,10 +8,11 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"time"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 
 	"github.com/xitonix/trubka/internal"
 )
@@ -164,6 +165,90 @@ func (m *Manager) CreatePartitions(topic string, partitions int32) error {
 	return m.admin.CreatePartitions(topic, partitions, nil, false)
 }
 
+// AlterTopicConfig sets or deletes the configurations of the specified topic.
+//
+// The method returns the old and the new values of the changed configs.
+// No changes will be applied to the topic in validateOnly mode.
+func (m *Manager) AlterTopicConfig(ctx context.Context, topic string, set map[string]string, remove []string, validateOnly bool) ([]*ConfigChange, error) {
+	return m.alterConfig(ctx, sarama.TopicResource, topic, set, remove, validateOnly)
+}
+
+// AlterBrokerConfig sets or deletes the dynamic configurations of the specified broker.
+//
+// The method returns the old and the new values of the changed configs.
+// No changes will be applied to the broker in validateOnly mode.
+func (m *Manager) AlterBrokerConfig(ctx context.Context, addressOrID string, set map[string]string, remove []string, validateOnly bool) ([]*ConfigChange, error) {
+	broker, err := m.findBroker(addressOrID)
+	if err != nil {
+		return nil, err
+	}
+	return m.alterConfig(ctx, sarama.BrokerResource, strconv.FormatInt(int64(broker.ID), 10), set, remove, validateOnly)
+}
+
+// ListACLs returns the ACL entries matching the filter.
+//
+// The empty fields of the filter match any value.
+func (m *Manager) ListACLs(ctx context.Context, filter *ACL) ([]*ACL, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		aclFilter, err := filter.toFilter()
+		if err != nil {
+			return nil, err
+		}
+		m.Log(internal.Verbose, "Retrieving the ACLs from the server")
+		resources, err := m.admin.ListAcls(aclFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the ACLs: %w", err)
+		}
+		return fromResourceACLs(resources), nil
+	}
+}
+
+// CreateACL creates a new ACL entry.
+func (m *Manager) CreateACL(acl *ACL) error {
+	resource, binding, err := acl.toBinding()
+	if err != nil {
+		return err
+	}
+	m.Logf(internal.Verbose, "Creating a new ACL for %s", acl.Principal)
+	err = m.admin.CreateACLs([]*sarama.ResourceAcls{
+		{
+			Resource: resource,
+			Acls:     []*sarama.Acl{&binding},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the ACL: %w", err)
+	}
+	return nil
+}
+
+// DeleteACLs deletes the ACL entries matching the filter and returns the deleted entries.
+//
+// The empty fields of the filter match any value.
+func (m *Manager) DeleteACLs(filter *ACL) ([]*ACL, error) {
+	aclFilter, err := filter.toFilter()
+	if err != nil {
+		return nil, err
+	}
+	m.Log(internal.Verbose, "Deleting the ACLs")
+	matches, err := m.admin.DeleteACL(aclFilter, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete the ACLs: %w", err)
+	}
+	result := make([]*ACL, 0, len(matches))
+	for _, match := range matches {
+		if !errors.Is(match.Err, sarama.ErrNoError) {
+			return nil, fmt.Errorf("failed to delete the ACLs: %w", match.Err)
+		}
+		result = append(result, newACL(match.Resource, &match.Acl))
+	}
+	sort.Sort(ACLsByResource(result))
+	return result, nil
+}
+
 // DescribeCluster returns detailed information about the cluster.
 func (m *Manager) DescribeCluster(ctx context.Context, includeConfig bool) (*ClusterMetadata, error) {
 	m.Log(internal.Verbose, "Retrieving broker list from the server")
@@ -190,6 +275,53 @@ func (m *Manager) DescribeCluster(ctx context.Context, includeConfig bool) (*Clu
 	return result, nil
 }
 
+// CheckClusterHealth checks the replication status of the topic partitions, along with the availability of the
+// brokers and the controller.
+//
+// All the topics will be checked if the topic filter is nil.
+func (m *Manager) CheckClusterHealth(ctx context.Context, topicFilter *regexp.Regexp, thresholds HealthThresholds) (*ClusterHealth, error) {
+	m.Log(internal.Verbose, "Retrieving the cluster metadata from the server")
+	servers, controllerID, err := m.admin.DescribeCluster()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the cluster: %w", err)
+	}
+	brokers := make([]*Broker, len(servers))
+	for i, server := range servers {
+		brokers[i] = NewBroker(server, controllerID)
+	}
+	sort.Sort(BrokersByID(brokers))
+
+	m.Log(internal.Verbose, "Retrieving the topic list from the server")
+	all, err := m.client.Topics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the topic list: %w", err)
+	}
+	topics := make([]string, 0, len(all))
+	for _, topic := range all {
+		if topicFilter != nil && !topicFilter.MatchString(topic) {
+			m.Logf(internal.SuperVerbose, "Filtering out %s topic", topic)
+			continue
+		}
+		topics = append(topics, topic)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	metadata := make([]*sarama.TopicMetadata, 0)
+	if len(topics) > 0 {
+		m.Logf(internal.Verbose, "Retrieving the partition metadata of %d topics", len(topics))
+		metadata, err = m.admin.DescribeTopics(topics)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the topics: %w", err)
+		}
+	}
+	return newClusterHealth(brokers, metadata, thresholds), nil
+}
+
 // GetTopics returns a list of all the topics on the server.
 func (m *Manager) GetTopics(ctx context.Context, filter *regexp.Regexp) ([]Topic, error) {
 	m.Log(internal.Verbose, "Retrieving topic list from the server")
@@ -353,6 +485,7 @@ func (m *Manager) DescribeTopic(ctx context.Context, topic string, includeConfig
 				Replicas:        m.toBrokers(pm.Replicas),
 				OfflineReplicas: m.toBrokers(pm.OfflineReplicas),
 				Leader:          m.getBrokerByID(pm.Leader),
+				PreferredLeader: pm.Leader >= 0 && len(pm.Replicas) > 0 && pm.Replicas[0] == pm.Leader,
 				Offset:          -1,
 			}
 			if includeOffsets {
@@ -422,7 +555,7 @@ func (m *Manager) DescribeBroker(ctx context.Context, addressOrID string, includ
 			if err != nil {
 				return nil, fmt.Errorf("failed to retrieve the API versions from %s: %w", addressOrID, err)
 			}
-			for _, api := range apiResponse.ApiVersions {
+			for _, api := range apiResponse.ApiKeys {
 				m.Logf(internal.Chatty, "API key %d retrieved from broker %s", api.ApiKey, addressOrID)
 				name := kafkaAPINames[api.ApiKey]
 				meta.APIs = append(meta.APIs, newAPI(name, api.ApiKey, api.MinVersion, api.MaxVersion))
@@ -532,6 +665,389 @@ func (m *Manager) GetGroupOffsets(ctx context.Context, group string, topicFilter
 	return result, nil
 }
 
+// GetGroupOffsetsReset calculates the new offsets of the consumer group, without committing them to the server.
+//
+// If no topics are specified, the offsets of all the topics for which the group has committed offsets will be reset.
+// The new offsets are calculated based on the start checkpoints. If checkpoints is nil, the current offsets will be shifted by shiftBy messages instead.
+// The method returns an error if the group is not empty.
+func (m *Manager) GetGroupOffsetsReset(ctx context.Context, group string, topics []string, checkpoints *PartitionCheckpoints, shiftBy int64) (GroupOffsetsReset, error) {
+	result := make(GroupOffsetsReset)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		err := m.ensureGroupIsEmpty(group)
+		if err != nil {
+			return nil, err
+		}
+
+		var topicPartitions map[string][]int32
+		if len(topics) > 0 {
+			topicPartitions = make(map[string][]int32)
+			for _, topic := range topics {
+				m.Logf(internal.VeryVerbose, "Retrieving the partitions of %s topic", topic)
+				partitions, err := m.client.Partitions(topic)
+				if err != nil {
+					return nil, fmt.Errorf("failed to retrieve the partitions of %s topic: %w", topic, err)
+				}
+				topicPartitions[topic] = partitions
+			}
+		}
+
+		m.Logf(internal.VeryVerbose, "Retrieving the offsets for %s consumer group", group)
+		cgOffsets, err := m.admin.ListConsumerGroupOffsets(group, topicPartitions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the consumer group offsets: %w", err)
+		}
+
+		for topic, blocks := range cgOffsets.Blocks {
+			for partition, block := range blocks {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				default:
+					if !errors.Is(block.Err, sarama.ErrNoError) {
+						return nil, fmt.Errorf("failed to retrieve the offset of partition %d of %s topic: %w", partition, topic, block.Err)
+					}
+					if topicPartitions == nil && block.Offset < 0 {
+						continue
+					}
+					reset, err := m.getPartitionOffsetReset(topic, partition, block.Offset, checkpoints, shiftBy)
+					if err != nil {
+						return nil, err
+					}
+					if reset != nil {
+						result[topic] = append(result[topic], *reset)
+					}
+				}
+			}
+		}
+	}
+
+	for _, partitions := range result {
+		sort.Slice(partitions, func(i, j int) bool {
+			return partitions[i].Partition < partitions[j].Partition
+		})
+	}
+	return result, nil
+}
+
+// ResetGroupOffsets commits the new offsets of the consumer group to the server.
+//
+// The method returns an error if the group is not empty.
+func (m *Manager) ResetGroupOffsets(ctx context.Context, group string, offsets GroupOffsetsReset) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		err := m.ensureGroupIsEmpty(group)
+		if err != nil {
+			return err
+		}
+
+		m.Logf(internal.VeryVerbose, "Retrieving %s group's coordinator from the server", group)
+		coordinator, err := m.client.Coordinator(group)
+		if err != nil {
+			return fmt.Errorf("failed to fetch the group coordinator details: %w", err)
+		}
+
+		request := &sarama.OffsetCommitRequest{
+			Version:                 1,
+			ConsumerGroup:           group,
+			ConsumerGroupGeneration: sarama.GroupGenerationUndefined,
+		}
+		for topic, partitions := range offsets {
+			for _, partition := range partitions {
+				request.AddBlock(topic, partition.Partition, partition.Target, sarama.ReceiveTime, "")
+			}
+		}
+
+		m.Logf(internal.Verbose, "Committing the new offsets of %s consumer group", group)
+		response, err := coordinator.CommitOffset(request)
+		if err != nil {
+			return fmt.Errorf("failed to commit the consumer group offsets: %w", err)
+		}
+		for topic, partitions := range response.Errors {
+			for partition, kErr := range partitions {
+				if !errors.Is(kErr, sarama.ErrNoError) {
+					return fmt.Errorf("failed to commit the offset of partition %d of %s topic: %w", partition, topic, kErr)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetRecordsDeletion calculates the records to be deleted from the beginning of the topic partitions, without deleting them.
+//
+// The target offset of each partition is calculated based on the start checkpoints.
+// The partitions with no records to delete will not be included in the result.
+func (m *Manager) GetRecordsDeletion(ctx context.Context, topic string, checkpoints *PartitionCheckpoints) ([]RecordsDeletion, error) {
+	result := make([]RecordsDeletion, 0)
+	m.Logf(internal.VeryVerbose, "Retrieving the partitions of %s topic", topic)
+	partitions, err := m.client.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the partitions of %s topic: %w", topic, err)
+	}
+	for _, partition := range partitions {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			pair := checkpoints.get(partition)
+			if pair == nil {
+				continue
+			}
+			m.Logf(internal.SuperVerbose, "Retrieving the earliest and latest offsets of partition %d of %s topic from the server", partition, topic)
+			earliest, err := m.client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return nil, err
+			}
+			latest, err := m.client.GetOffset(topic, partition, sarama.OffsetNewest)
+			if err != nil {
+				return nil, err
+			}
+			before, err := pair.from.resetOffset(earliest, latest, func(millis int64) (int64, error) {
+				return m.client.GetOffset(topic, partition, millis)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate the target offset of partition %d of %s topic: %w", partition, topic, err)
+			}
+			deletion := RecordsDeletion{
+				Partition: partition,
+				Earliest:  earliest,
+				Latest:    latest,
+				Before:    before,
+			}
+			if deletion.Count() > 0 {
+				result = append(result, deletion)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Partition < result[j].Partition
+	})
+	return result, nil
+}
+
+// DeleteRecords deletes the records of the topic partitions, by moving the log start offsets forward.
+func (m *Manager) DeleteRecords(topic string, deletions []RecordsDeletion) error {
+	partitionOffsets := make(map[int32]int64)
+	for _, deletion := range deletions {
+		partitionOffsets[deletion.Partition] = deletion.Before
+	}
+	m.Logf(internal.Verbose, "Deleting the records of %s topic", topic)
+	err := m.admin.DeleteRecords(topic, partitionOffsets)
+	if err != nil {
+		return fmt.Errorf("failed to delete the records: %w", err)
+	}
+	return nil
+}
+
+// GetReassignmentPlan returns the current replica assignments of the topics, along with a plan to distribute the replicas evenly across the specified brokers.
+func (m *Manager) GetReassignmentPlan(ctx context.Context, topics []string, brokerIDs []int32) (*ReassignmentPlan, *ReassignmentPlan, error) {
+	for _, id := range brokerIDs {
+		if _, ok := m.serversByID[id]; !ok {
+			return nil, nil, fmt.Errorf("broker %d not found", id)
+		}
+	}
+	current, err := m.getReplicaAssignments(ctx, topics)
+	if err != nil {
+		return nil, nil, err
+	}
+	proposed, err := balancedPlan(current, brokerIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, proposed, nil
+}
+
+// GetReplicaAssignments returns the current replica assignments of the partitions in the plan.
+func (m *Manager) GetReplicaAssignments(ctx context.Context, plan *ReassignmentPlan) (*ReassignmentPlan, error) {
+	all, err := m.getReplicaAssignments(ctx, plan.Topics())
+	if err != nil {
+		return nil, err
+	}
+	current := newReassignmentPlan()
+	for _, p := range plan.Partitions {
+		replicas := all.Replicas(p.Topic, p.Partition)
+		if replicas == nil {
+			return nil, fmt.Errorf("partition %d of %s topic not found", p.Partition, p.Topic)
+		}
+		current.Partitions = append(current.Partitions, &PartitionReassignment{
+			Topic:     p.Topic,
+			Partition: p.Partition,
+			Replicas:  replicas,
+		})
+	}
+	current.sort()
+	return current, nil
+}
+
+// ExecuteReassignmentPlan starts reassigning the partitions in the plan.
+//
+// The call returns as soon as the reassignments have been accepted by the controller.
+func (m *Manager) ExecuteReassignmentPlan(ctx context.Context, plan *ReassignmentPlan) error {
+	for _, id := range plan.brokers() {
+		if _, ok := m.serversByID[id]; !ok {
+			return fmt.Errorf("broker %d not found", id)
+		}
+	}
+	for _, topic := range plan.Topics() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			m.Logf(internal.VeryVerbose, "Retrieving the partitions of %s topic", topic)
+			partitions, err := m.client.Partitions(topic)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve the partitions of %s topic: %w", topic, err)
+			}
+			for _, p := range plan.Partitions {
+				if p.Topic == topic && int(p.Partition) >= len(partitions) {
+					return fmt.Errorf("partition %d of %s topic not found", p.Partition, topic)
+				}
+			}
+			m.Logf(internal.VeryVerbose, "Retrieving the ongoing partition reassignments of %s topic", topic)
+			ongoing, err := m.admin.ListPartitionReassignments(topic, partitions)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve the partition reassignments of %s topic: %w", topic, err)
+			}
+			// The partition IDs are the indices of the assignments and every index is submitted to the controller.
+			// Sending an empty assignment would cancel the ongoing reassignment of the partition, and sending the
+			// current replicas of a partition which is being reassigned would replace its target with the union of
+			// the adding and removing replicas. So the partitions which are not in the plan are resubmitted with the
+			// target replicas of their ongoing reassignment, or their current replicas if they are not being reassigned.
+			assignments := make([][]int32, len(partitions))
+			for _, partition := range partitions {
+				if int(partition) >= len(assignments) {
+					return fmt.Errorf("unexpected partition %d of %s topic", partition, topic)
+				}
+				replicas := plan.Replicas(topic, partition)
+				if replicas == nil {
+					if status, ok := ongoing[topic][partition]; ok {
+						replicas = targetReplicas(status)
+					} else {
+						replicas, err = m.client.Replicas(topic, partition)
+						if err != nil {
+							return fmt.Errorf("failed to retrieve the replicas of partition %d of %s topic: %w", partition, topic, err)
+						}
+					}
+				}
+				assignments[partition] = replicas
+			}
+			m.Logf(internal.Verbose, "Reassigning the partitions of %s topic", topic)
+			err = m.admin.AlterPartitionReassignments(topic, assignments)
+			if err != nil {
+				return fmt.Errorf("failed to reassign the partitions of %s topic: %w", topic, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetReassignmentStatus returns the ongoing partition reassignments of the specified topics.
+//
+// All the topics will be checked if no topics have been specified.
+func (m *Manager) GetReassignmentStatus(ctx context.Context, topics []string) ([]*ReassignmentStatus, error) {
+	if len(topics) == 0 {
+		m.Logf(internal.Verbose, "Retrieving the topic list from the server")
+		err := m.client.RefreshMetadata()
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh the metadata: %w", err)
+		}
+		topics, err = m.client.Topics()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the topic list: %w", err)
+		}
+	}
+	result := make([]*ReassignmentStatus, 0)
+	for _, topic := range topics {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			partitions, err := m.client.Partitions(topic)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve the partitions of %s topic: %w", topic, err)
+			}
+			m.Logf(internal.VeryVerbose, "Retrieving the ongoing partition reassignments of %s topic", topic)
+			statuses, err := m.admin.ListPartitionReassignments(topic, partitions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve the partition reassignments of %s topic: %w", topic, err)
+			}
+			for partition, status := range statuses[topic] {
+				result = append(result, &ReassignmentStatus{
+					Topic:     topic,
+					Partition: partition,
+					Replicas:  status.Replicas,
+					Adding:    status.AddingReplicas,
+					Removing:  status.RemovingReplicas,
+				})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Topic != result[j].Topic {
+			return result[i].Topic < result[j].Topic
+		}
+		return result[i].Partition < result[j].Partition
+	})
+	return result, nil
+}
+
+// ElectLeaders triggers the leader election of the topic partitions.
+//
+// All the partitions of the topic will be included if no partitions have been specified.
+// All the partitions of all the topics will be included if the topic is empty.
+func (m *Manager) ElectLeaders(ctx context.Context, electionType string, topic string, partitions []int32) ([]*LeaderElection, error) {
+	election, err := toElectionType(electionType)
+	if err != nil {
+		return nil, err
+	}
+	if !m.client.Config().Version.IsAtLeast(sarama.V2_4_0_0) {
+		return nil, errors.New("leader election requires Kafka v2.4.0 or newer")
+	}
+
+	topics := []string{topic}
+	if internal.IsEmpty(topic) {
+		if len(partitions) > 0 {
+			return nil, errors.New("the topic must be specified to elect the leaders of specific partitions")
+		}
+		m.Logf(internal.Verbose, "Retrieving the topic list from the server")
+		topics, err = m.client.Topics()
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the topic list: %w", err)
+		}
+	}
+
+	request := make(map[string][]int32)
+	for _, t := range topics {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			if len(partitions) > 0 {
+				request[t] = partitions
+				continue
+			}
+			m.Logf(internal.VeryVerbose, "Retrieving the partitions of %s topic", t)
+			all, err := m.client.Partitions(t)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve the partitions of %s topic: %w", t, err)
+			}
+			request[t] = all
+		}
+	}
+
+	m.Logf(internal.Verbose, "Electing the %s leaders", electionType)
+	results, err := m.admin.ElectLeaders(election, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to elect the leaders: %w", err)
+	}
+	return fromElectionResults(results), nil
+}
+
 // GetTopicOffsets returns the current partition offsets of the specified topics.
 func (m *Manager) GetTopicOffsets(ctx context.Context, topic string, currentPartitionOffsets PartitionOffset) (PartitionOffset, error) {
 	result := make(PartitionOffset)
@@ -608,6 +1124,179 @@ func (m *Manager) findBroker(idOrAddress string) (*Broker, error) {
 	return nil, fmt.Errorf("broker %v not found", idOrAddress)
 }
 
+func (m *Manager) getReplicaAssignments(ctx context.Context, topics []string) (*ReassignmentPlan, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("no topics have been specified")
+	}
+	result := newReassignmentPlan()
+	for _, topic := range topics {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			m.Logf(internal.VeryVerbose, "Retrieving the partitions of %s topic", topic)
+			partitions, err := m.client.Partitions(topic)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve the partitions of %s topic: %w", topic, err)
+			}
+			for _, partition := range partitions {
+				m.Logf(internal.SuperVerbose, "Retrieving the replicas of partition %d of %s topic", partition, topic)
+				replicas, err := m.client.Replicas(topic, partition)
+				if err != nil {
+					return nil, fmt.Errorf("failed to retrieve the replicas of partition %d of %s topic: %w", partition, topic, err)
+				}
+				result.Partitions = append(result.Partitions, &PartitionReassignment{
+					Topic:     topic,
+					Partition: partition,
+					Replicas:  replicas,
+				})
+			}
+		}
+	}
+	result.sort()
+	return result, nil
+}
+
+func (m *Manager) getPartitionOffsetReset(topic string, partition int32, current int64, checkpoints *PartitionCheckpoints, shiftBy int64) (*PartitionOffsetReset, error) {
+	var target int64
+	m.Logf(internal.SuperVerbose, "Retrieving the earliest and latest offsets of partition %d of %s topic from the server", partition, topic)
+	earliest, err := m.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := m.client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpoints == nil {
+		target, err = shiftOffset(current, shiftBy, earliest, latest)
+	} else {
+		pair := checkpoints.get(partition)
+		if pair == nil {
+			return nil, nil
+		}
+		target, err = pair.from.resetOffset(earliest, latest, func(millis int64) (int64, error) {
+			return m.client.GetOffset(topic, partition, millis)
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate the new offset of partition %d of %s topic: %w", partition, topic, err)
+	}
+
+	return &PartitionOffsetReset{
+		Partition: partition,
+		Earliest:  earliest,
+		Latest:    latest,
+		Current:   current,
+		Target:    target,
+	}, nil
+}
+
+// ensureGroupIsEmpty returns an error if the consumer group has active members.
+//
+// Dead groups are accepted, since they do not exist on the server until the offsets are committed.
+func (m *Manager) ensureGroupIsEmpty(group string) error {
+	m.Logf(internal.Verbose, "Retrieving %s consumer group state from the server", group)
+	details, err := m.admin.DescribeConsumerGroups([]string{group})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the consumer group details: %w", err)
+	}
+	if len(details) != 1 {
+		return errors.New("failed to retrieve consumer group details from the server")
+	}
+	state := details[0].State
+	if state != "Empty" && state != "Dead" {
+		return fmt.Errorf("the offsets of %s group can only be reset when the group is empty. Stop all the consumers and try again (current state: %s)", group, state)
+	}
+	return nil
+}
+
+func (m *Manager) alterConfig(ctx context.Context,
+	resourceType sarama.ConfigResourceType,
+	resourceName string,
+	set map[string]string,
+	remove []string,
+	validateOnly bool) ([]*ConfigChange, error) {
+	if len(set) == 0 && len(remove) == 0 {
+		return nil, errors.New("no configuration changes have been requested")
+	}
+	for _, name := range remove {
+		if _, ok := set[name]; ok {
+			return nil, fmt.Errorf("%s cannot be set and deleted at the same time", name)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	m.Logf(internal.Verbose, "Retrieving the current configurations of %s from the server", resourceName)
+	entries, err := m.admin.DescribeConfig(sarama.ConfigResource{
+		Type: resourceType,
+		Name: resourceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the current configurations: %w", err)
+	}
+	current := make(map[string]sarama.ConfigEntry)
+	for _, entry := range entries {
+		current[entry.Name] = entry
+	}
+
+	changes := make([]*ConfigChange, 0, len(set)+len(remove))
+	for name, value := range set {
+		changes = append(changes, &ConfigChange{
+			Name:     name,
+			OldValue: current[name].Value,
+			NewValue: value,
+		})
+	}
+	for _, name := range remove {
+		changes = append(changes, &ConfigChange{
+			Name:     name,
+			OldValue: current[name].Value,
+			Deleted:  true,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+
+	if m.client.Config().Version.IsAtLeast(sarama.V2_3_0_0) {
+		m.Logf(internal.Verbose, "Altering the configurations of %s incrementally", resourceName)
+		alterations := make(map[string]sarama.IncrementalAlterConfigsEntry)
+		for name, value := range set {
+			value := value
+			alterations[name] = sarama.IncrementalAlterConfigsEntry{
+				Operation: sarama.IncrementalAlterConfigsOperationSet,
+				Value:     &value,
+			}
+		}
+		for _, name := range remove {
+			alterations[name] = sarama.IncrementalAlterConfigsEntry{
+				Operation: sarama.IncrementalAlterConfigsOperationDelete,
+			}
+		}
+		err = m.admin.IncrementalAlterConfig(resourceType, resourceName, alterations, validateOnly)
+	} else {
+		// AlterConfigs replaces all the dynamic configurations of the resource. The existing dynamic values must be sent along with the changes.
+		m.Logf(internal.Verbose, "Altering the configurations of %s", resourceName)
+		var alterations map[string]*string
+		alterations, err = toAlterConfigEntries(resourceType, entries, set, remove)
+		if err != nil {
+			return nil, err
+		}
+		err = m.admin.AlterConfig(resourceType, resourceName, alterations, validateOnly)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to alter the configurations: %w", err)
+	}
+	return changes, nil
+}
+
 func (m *Manager) loadConfig(resourceType sarama.ConfigResourceType, resourceName string) ([]*ConfigEntry, error) {
 	entries, err := m.admin.DescribeConfig(sarama.ConfigResource{
 		Type:        resourceType,
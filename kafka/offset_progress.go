@@ -0,0 +1,35 @@
+package kafka
+
+import "time"
+
+// OffsetProgress represents the changes of a partition offset pair between two snapshots.
+type OffsetProgress struct {
+	// LagDelta the lag difference since the previous snapshot. A negative value means the consumer is catching up.
+	LagDelta int64
+	// Rate the number of messages consumed per second since the previous snapshot.
+	Rate float64
+}
+
+// Progress calculates the progress of each partition since the previous snapshot.
+//
+// The partitions which do not exist in the previous snapshot will be excluded from the result.
+func (p PartitionOffset) Progress(previous PartitionOffset, elapsed time.Duration) map[int32]OffsetProgress {
+	result := make(map[int32]OffsetProgress)
+	if len(previous) == 0 {
+		return result
+	}
+	for partition, offset := range p {
+		prev, ok := previous[partition]
+		if !ok {
+			continue
+		}
+		progress := OffsetProgress{
+			LagDelta: offset.Lag() - prev.Lag(),
+		}
+		if prev.Current >= 0 && offset.Current > prev.Current && elapsed > 0 {
+			progress.Rate = float64(offset.Current-prev.Current) / elapsed.Seconds()
+		}
+		result[partition] = progress
+	}
+	return result
+}
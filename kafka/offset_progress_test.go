@@ -0,0 +1,66 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPartitionOffsetProgress(t *testing.T) {
+	testCases := []struct {
+		title    string
+		previous PartitionOffset
+		current  PartitionOffset
+		elapsed  time.Duration
+		expected map[int32]OffsetProgress
+	}{
+		{
+			title:    "no previous snapshot",
+			current:  PartitionOffset{0: {Latest: 100, Current: 50}},
+			elapsed:  time.Second,
+			expected: map[int32]OffsetProgress{},
+		},
+		{
+			title:    "consumer catching up",
+			previous: PartitionOffset{0: {Latest: 100, Current: 50}},
+			current:  PartitionOffset{0: {Latest: 110, Current: 90}},
+			elapsed:  2 * time.Second,
+			expected: map[int32]OffsetProgress{0: {LagDelta: -30, Rate: 20}},
+		},
+		{
+			title:    "consumer falling behind",
+			previous: PartitionOffset{0: {Latest: 100, Current: 50}},
+			current:  PartitionOffset{0: {Latest: 200, Current: 50}},
+			elapsed:  time.Second,
+			expected: map[int32]OffsetProgress{0: {LagDelta: 100}},
+		},
+		{
+			title:    "no committed offset in the previous snapshot",
+			previous: PartitionOffset{0: {Latest: 100, Current: -1}},
+			current:  PartitionOffset{0: {Latest: 100, Current: 40}},
+			elapsed:  time.Second,
+			expected: map[int32]OffsetProgress{0: {LagDelta: -41}},
+		},
+		{
+			title:    "new partition",
+			previous: PartitionOffset{0: {Latest: 10, Current: 10}},
+			current:  PartitionOffset{0: {Latest: 10, Current: 10}, 1: {Latest: 5, Current: 0}},
+			elapsed:  time.Second,
+			expected: map[int32]OffsetProgress{0: {}},
+		},
+		{
+			title:    "zero elapsed time",
+			previous: PartitionOffset{0: {Latest: 100, Current: 50}},
+			current:  PartitionOffset{0: {Latest: 100, Current: 60}},
+			expected: map[int32]OffsetProgress{0: {LagDelta: -10}},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual := tC.current.Progress(tC.previous, tC.elapsed)
+			if !reflect.DeepEqual(actual, tC.expected) {
+				t.Errorf("Expected: %+v, Actual: %+v", tC.expected, actual)
+			}
+		})
+	}
+}
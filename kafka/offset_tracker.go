@@ -0,0 +1,27 @@
+package kafka
+
+// offsetTracker counts the offsets skipped between the consecutive messages of a partition.
+//
+// The consumer does not deliver the transaction markers (control records) and, in read_committed mode, the
+// messages of the aborted transactions. The compacted or deleted records also leave gaps in the offsets.
+type offsetTracker struct {
+	next int64
+}
+
+// newOffsetTracker creates a new tracker. The gaps before the first message will not be counted if the start
+// offset is negative (eg. oldest or newest).
+func newOffsetTracker(start int64) *offsetTracker {
+	return &offsetTracker{
+		next: start,
+	}
+}
+
+// skipped returns the number of offsets skipped since the previous message.
+func (t *offsetTracker) skipped(offset int64) int64 {
+	var skipped int64
+	if t.next >= 0 && offset > t.next {
+		skipped = offset - t.next
+	}
+	t.next = offset + 1
+	return skipped
+}
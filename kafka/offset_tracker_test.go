@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	testCases := []struct {
+		title    string
+		start    int64
+		offsets  []int64
+		expected []int64
+	}{
+		{
+			title:    "consecutive offsets",
+			start:    10,
+			offsets:  []int64{10, 11, 12},
+			expected: []int64{0, 0, 0},
+		},
+		{
+			title:    "gap before the first message",
+			start:    10,
+			offsets:  []int64{12, 13},
+			expected: []int64{2, 0},
+		},
+		{
+			title:    "unknown start offset",
+			start:    sarama.OffsetNewest,
+			offsets:  []int64{100, 101},
+			expected: []int64{0, 0},
+		},
+		{
+			title:    "transaction markers and aborted messages",
+			start:    sarama.OffsetOldest,
+			offsets:  []int64{0, 1, 3, 8, 9},
+			expected: []int64{0, 0, 1, 4, 0},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			tracker := newOffsetTracker(tC.start)
+			for i, offset := range tC.offsets {
+				actual := tracker.skipped(offset)
+				if actual != tC.expected[i] {
+					t.Errorf("Offset %d: Expected skipped: %d, Actual: %d", offset, tC.expected[i], actual)
+				}
+			}
+		})
+	}
+}
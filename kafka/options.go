@@ -4,14 +4,17 @@ import (
 	"crypto/tls"
 	"io"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 
 	"github.com/xitonix/trubka/internal"
 )
 
 var (
 	// DefaultClusterVersion default cluster version.
-	DefaultClusterVersion = sarama.MaxVersion.String()
+	//
+	// The version is pinned, instead of following the latest version supported by sarama, so that upgrading the
+	// client library does not change the protocol versions used to talk to the existing clusters.
+	DefaultClusterVersion = sarama.V2_6_0_0.String()
 )
 
 // Options holds the configuration settings for kafka consumer.
@@ -24,6 +27,8 @@ type Options struct {
 	TLS       *tls.Config
 	sasl      *sasl
 	logWriter io.Writer
+	producer  *ProducerSettings
+	isolation string
 }
 
 // NewOptions creates a new Options object with default values.
@@ -49,9 +54,15 @@ func WithClusterVersion(version string) Option {
 }
 
 // WithSASL enables SASL authentication.
-func WithSASL(mechanism, username, password, handshakeVersion string) Option {
+//
+// The mechanism specific settings (eg. the OAuth token or the Kerberos settings) can be provided using the SASL
+// options. The password will only be read if the mechanism is not set to none and it requires a password.
+func WithSASL(mechanism, username string, password SASLPassword, handshakeVersion string, saslOptions ...SASLOption) Option {
 	return func(options *Options) {
-		options.sasl = newSASL(mechanism, username, password, SASLHandshakeVersion(handshakeVersion))
+		if password == nil {
+			password = StaticPassword("")
+		}
+		options.sasl = newSASL(mechanism, username, password, SASLHandshakeVersion(handshakeVersion), saslOptions...)
 	}
 }
 
@@ -68,3 +79,17 @@ func WithTLS(tls *tls.Config) Option {
 		options.TLS = tls
 	}
 }
+
+// WithProducerSettings overrides the default producer settings.
+func WithProducerSettings(settings *ProducerSettings) Option {
+	return func(options *Options) {
+		options.producer = settings
+	}
+}
+
+// WithIsolationLevel sets the isolation level of the consumer (read_committed or read_uncommitted).
+func WithIsolationLevel(level string) Option {
+	return func(options *Options) {
+		options.isolation = level
+	}
+}
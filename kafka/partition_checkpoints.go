@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -80,6 +81,20 @@ func NewPartitionCheckpoints(from, to []string, exclusive bool) (*PartitionCheck
 	}, nil
 }
 
+// NewTargetCheckpoints creates a new instance of partition checkpoints to be used as the target offsets of the admin operations.
+//
+// Only the explicitly requested partitions (using # syntax) will be targeted, unless a global value has been provided.
+func NewTargetCheckpoints(values []string) (*PartitionCheckpoints, error) {
+	exclusive := true
+	for _, value := range values {
+		if !strings.Contains(value, "#") {
+			exclusive = false
+			break
+		}
+	}
+	return NewPartitionCheckpoints(values, nil, exclusive)
+}
+
 // From returns the comma separated string of all the start checkpoints.
 func (p *PartitionCheckpoints) From() string {
 	return p.from
@@ -90,6 +105,24 @@ func (p *PartitionCheckpoints) To() string {
 	return p.to
 }
 
+// groupInitialOffset returns the offset from which a consumer group starts consuming, if no offset has been committed for the group yet.
+//
+// Consumer groups only accept a global predefined start checkpoint (eg. newest or oldest).
+// Partition specific and stop checkpoints are not supported, because the partitions are assigned by the group coordinator.
+func (p *PartitionCheckpoints) groupInitialOffset() (int64, error) {
+	if len(p.partitionCheckpoints) > 1 {
+		return 0, errors.New("partition specific checkpoints are not supported in consumer group mode")
+	}
+	pair := p.partitionCheckpoints[allPartitions]
+	if pair.to != nil {
+		return 0, errors.New("stop checkpoints are not supported in consumer group mode")
+	}
+	if pair.from.mode != predefinedMode {
+		return 0, fmt.Errorf("%s is not an acceptable start checkpoint in consumer group mode. Use newest or oldest instead", pair.from.String())
+	}
+	return pair.from.offset, nil
+}
+
 // get returns the checkpoint for the specified partition.
 //
 // In `exclusive` mode, if the partition checkpoint has not explicitly defined by the user (using # syntax) this function returns `nil`.
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/IBM/sarama"
 	"github.com/araddon/dateparse"
 )
 
@@ -482,3 +483,128 @@ func randomInt32(min, max int32) int32 {
 	//nolint:gosec
 	return rand.Int31n(max-min+1) + min
 }
+
+func TestPartitionCheckpointsGroupInitialOffset(t *testing.T) {
+	testCases := []struct {
+		title         string
+		from          []string
+		to            []string
+		expected      int64
+		expectedError string
+	}{
+		{
+			title:    "no start checkpoint",
+			expected: sarama.OffsetNewest,
+		},
+		{
+			title:    "newest start checkpoint",
+			from:     []string{"newest"},
+			expected: sarama.OffsetNewest,
+		},
+		{
+			title:    "oldest start checkpoint",
+			from:     []string{"oldest"},
+			expected: sarama.OffsetOldest,
+		},
+		{
+			title:         "explicit start checkpoint",
+			from:          []string{"100"},
+			expectedError: "is not an acceptable start checkpoint in consumer group mode",
+		},
+		{
+			title:         "timestamp start checkpoint",
+			from:          []string{"2020-01-01T12:00:00+10:00"},
+			expectedError: "is not an acceptable start checkpoint in consumer group mode",
+		},
+		{
+			title:         "local start checkpoint",
+			from:          []string{"local"},
+			expectedError: "is not an acceptable start checkpoint in consumer group mode",
+		},
+		{
+			title:         "partition specific start checkpoint",
+			from:          []string{"1#oldest"},
+			expectedError: "partition specific checkpoints are not supported in consumer group mode",
+		},
+		{
+			title:         "stop checkpoint",
+			from:          []string{"oldest"},
+			to:            []string{"100"},
+			expectedError: "stop checkpoints are not supported in consumer group mode",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			checkpoints, err := NewPartitionCheckpoints(tC.from, tC.to, false)
+			if err != nil {
+				t.Fatalf("Did not expect an error, but received: '%v'", err)
+			}
+			actual, err := checkpoints.groupInitialOffset()
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if tC.expectedError != "" {
+				return
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected initial offset: %v, Actual: %v", getOffsetString(tC.expected), getOffsetString(actual))
+			}
+		})
+	}
+}
+
+func TestNewTargetCheckpoints(t *testing.T) {
+	testCases := []struct {
+		title    string
+		values   []string
+		expected map[int32]*checkpoint
+	}{
+		{
+			title:  "global target applies to all the partitions",
+			values: []string{"100"},
+			expected: map[int32]*checkpoint{
+				0: newExplicitCheckpoint(100),
+				1: newExplicitCheckpoint(100),
+			},
+		},
+		{
+			title:  "partition specific targets only",
+			values: []string{"1#200"},
+			expected: map[int32]*checkpoint{
+				0: nil,
+				1: newExplicitCheckpoint(200),
+			},
+		},
+		{
+			title:  "partition specific and global targets",
+			values: []string{"1#200", "oldest"},
+			expected: map[int32]*checkpoint{
+				0: newPredefinedCheckpoint(true),
+				1: newExplicitCheckpoint(200),
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			checkpoints, err := NewTargetCheckpoints(tC.values)
+			if err != nil {
+				t.Fatalf("Did not expect an error, but received %s", err)
+			}
+			for partition, expected := range tC.expected {
+				pair := checkpoints.get(partition)
+				if expected == nil {
+					if pair != nil {
+						t.Errorf("Did not expect a checkpoint for partition %d, but received %s", partition, pair.from.String())
+					}
+					continue
+				}
+				if pair == nil {
+					t.Fatalf("Expected a checkpoint for partition %d, but received nil", partition)
+				}
+				if pair.from.offset != expected.offset || pair.from.mode != expected.mode {
+					t.Errorf("Expected checkpoint of partition %d: %s, Actual: %s", partition, expected.String(), pair.from.String())
+				}
+			}
+		})
+	}
+}
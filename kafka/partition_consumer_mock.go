@@ -4,7 +4,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 )
 
 type partitionConsumerMock struct {
@@ -72,3 +72,11 @@ func (p *partitionConsumerMock) Errors() <-chan *sarama.ConsumerError {
 func (p *partitionConsumerMock) HighWaterMarkOffset() int64 {
 	return p.offset + 1
 }
+
+func (p *partitionConsumerMock) Pause() {}
+
+func (p *partitionConsumerMock) Resume() {}
+
+func (p *partitionConsumerMock) IsPaused() bool {
+	return false
+}
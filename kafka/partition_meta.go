@@ -1,5 +1,8 @@
 package kafka
 
+// NonPreferredLeaderLabel non-preferred leader marker.
+const NonPreferredLeaderLabel = "!"
+
 // PartitionMeta represents partition metadata.
 type PartitionMeta struct {
 	// ID partition id.
@@ -8,6 +11,8 @@ type PartitionMeta struct {
 	Offset int64 `json:"offset"`
 	// Leader leader node.
 	Leader *Broker `json:"leader"`
+	// PreferredLeader is true if the leader is the preferred replica (the first replica in the assignment).
+	PreferredLeader bool `json:"preferred_leader"`
 	// Replicas replication nodes.
 	Replicas []*Broker `json:"replicas"`
 	// ISRs in-sync replicas.
@@ -16,6 +21,16 @@ type PartitionMeta struct {
 	OfflineReplicas []*Broker `json:"offline_replicas"`
 }
 
+// HasLeader returns true if the partition has a leader.
+func (p *PartitionMeta) HasLeader() bool {
+	return p.Leader != nil && p.Leader.ID >= 0
+}
+
+// NonPreferredLeader returns true if the partition has a leader which is not the preferred replica.
+func (p *PartitionMeta) NonPreferredLeader() bool {
+	return p.HasLeader() && !p.PreferredLeader
+}
+
 // PartitionMetaByID sorts partition metadata by partition ID.
 type PartitionMetaByID []*PartitionMeta
 
@@ -0,0 +1,42 @@
+package kafka
+
+import "testing"
+
+func TestPartitionMetaLeader(t *testing.T) {
+	testCases := []struct {
+		title                      string
+		meta                       *PartitionMeta
+		expectedHasLeader          bool
+		expectedNonPreferredLeader bool
+	}{
+		{
+			title:             "preferred leader",
+			meta:              &PartitionMeta{Leader: &Broker{ID: 1}, PreferredLeader: true},
+			expectedHasLeader: true,
+		},
+		{
+			title:                      "non-preferred leader",
+			meta:                       &PartitionMeta{Leader: &Broker{ID: 2}},
+			expectedHasLeader:          true,
+			expectedNonPreferredLeader: true,
+		},
+		{
+			title: "no leader",
+			meta:  &PartitionMeta{Leader: &Broker{ID: -1}},
+		},
+		{
+			title: "nil leader",
+			meta:  &PartitionMeta{},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			if actual := tC.meta.HasLeader(); actual != tC.expectedHasLeader {
+				t.Errorf("Expected has leader: %v, Actual: %v", tC.expectedHasLeader, actual)
+			}
+			if actual := tC.meta.NonPreferredLeader(); actual != tC.expectedNonPreferredLeader {
+				t.Errorf("Expected non-preferred leader: %v, Actual: %v", tC.expectedNonPreferredLeader, actual)
+			}
+		})
+	}
+}
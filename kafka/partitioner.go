@@ -0,0 +1,142 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// messageMetadata the metadata of the published messages.
+type messageMetadata struct {
+	// partition the partition to which the message should be published. The partitioner decides if not set.
+	partition *partitionRequest
+	// enqueued the time at which the message was handed over to the producer.
+	enqueued time.Time
+}
+
+// partitionRequest the partition to which the message should be published.
+type partitionRequest struct {
+	partition int32
+	// strict if true, publishing fails if the partition does not exist.
+	// Otherwise, the partition will be selected based on the key.
+	strict bool
+}
+
+// partitioner publishes the messages to the requested partition (if any), otherwise falls back to the selected partitioner.
+type partitioner struct {
+	fallback sarama.Partitioner
+}
+
+// newPartitioner creates a partitioner which falls back to the hash partitioner.
+func newPartitioner(topic string) sarama.Partitioner {
+	return &partitioner{
+		fallback: sarama.NewHashPartitioner(topic),
+	}
+}
+
+// partitionerConstructor returns the constructor of the partitioner with the specified name.
+func partitionerConstructor(name string) (sarama.PartitionerConstructor, error) {
+	var fallback sarama.PartitionerConstructor
+	switch name {
+	case HashPartitioner:
+		return newPartitioner, nil
+	case Murmur2Partitioner:
+		fallback = newMurmur2Partitioner
+	case RandomPartitioner:
+		fallback = sarama.NewRandomPartitioner
+	case RoundRobinPartitioner:
+		fallback = sarama.NewRoundRobinPartitioner
+	default:
+		return nil, fmt.Errorf("invalid partitioner %q. The partitioner must be one of %s", name, strings.Join(Partitioners, ", "))
+	}
+	return func(topic string) sarama.Partitioner {
+		return &partitioner{
+			fallback: fallback(topic),
+		}
+	}, nil
+}
+
+func (p *partitioner) Partition(message *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if metadata, ok := message.Metadata.(*messageMetadata); ok && metadata.partition != nil {
+		request := metadata.partition
+		if request.partition >= 0 && request.partition < numPartitions {
+			return request.partition, nil
+		}
+		if request.strict {
+			return 0, fmt.Errorf("partition %d does not exist. The topic has %d partitions", request.partition, numPartitions)
+		}
+	}
+	return p.fallback.Partition(message, numPartitions)
+}
+
+func (p *partitioner) RequiresConsistency() bool {
+	return p.fallback.RequiresConsistency()
+}
+
+// murmur2Partitioner selects the partition using the murmur2 hash of the key, the same way the default partitioner
+// of the Java client does. The messages without a key are published to random partitions.
+type murmur2Partitioner struct {
+	random sarama.Partitioner
+}
+
+func newMurmur2Partitioner(topic string) sarama.Partitioner {
+	return &murmur2Partitioner{
+		random: sarama.NewRandomPartitioner(topic),
+	}
+}
+
+func (p *murmur2Partitioner) Partition(message *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if message.Key == nil {
+		return p.random.Partition(message, numPartitions)
+	}
+	key, err := message.Key.Encode()
+	if err != nil {
+		return -1, err
+	}
+	return (murmur2(key) & 0x7fffffff) % numPartitions, nil
+}
+
+func (p *murmur2Partitioner) RequiresConsistency() bool {
+	return true
+}
+
+// murmur2 generates the 32-bit murmur2 hash of the input, compatible with the Java client's implementation.
+func murmur2(data []byte) int32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+	length := len(data)
+	h := seed ^ uint32(length)
+	blocks := length / 4
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[blocks*4:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
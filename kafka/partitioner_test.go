@@ -0,0 +1,157 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestPartitioner(t *testing.T) {
+	key := sarama.StringEncoder("key")
+	hashed, err := sarama.NewHashPartitioner("topic").Partition(&sarama.ProducerMessage{Key: key}, 3)
+	if err != nil {
+		t.Fatalf("Failed to calculate the hashed partition: %s", err)
+	}
+	testCases := []struct {
+		title         string
+		metadata      interface{}
+		numPartitions int32
+		expected      int32
+		expectedError string
+	}{
+		{
+			title:         "no requested partition",
+			numPartitions: 3,
+			expected:      hashed,
+		},
+		{
+			title:         "requested partition exists",
+			metadata:      &messageMetadata{partition: &partitionRequest{partition: 2}},
+			numPartitions: 3,
+			expected:      2,
+		},
+		{
+			title:         "requested partition does not exist",
+			metadata:      &messageMetadata{partition: &partitionRequest{partition: 5}},
+			numPartitions: 3,
+			expected:      hashed,
+		},
+		{
+			title:         "explicit partition exists",
+			metadata:      &messageMetadata{partition: &partitionRequest{partition: 1, strict: true}},
+			numPartitions: 3,
+			expected:      1,
+		},
+		{
+			title:         "explicit partition does not exist",
+			metadata:      &messageMetadata{partition: &partitionRequest{partition: 3, strict: true}},
+			numPartitions: 3,
+			expectedError: "partition 3 does not exist. The topic has 3 partitions",
+		},
+		{
+			title:         "no partition request",
+			metadata:      &messageMetadata{},
+			numPartitions: 3,
+			expected:      hashed,
+		},
+		{
+			title:         "unknown metadata",
+			metadata:      "2",
+			numPartitions: 3,
+			expected:      hashed,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			p := newPartitioner("topic")
+			actual, err := p.Partition(&sarama.ProducerMessage{Key: key, Metadata: tC.metadata}, tC.numPartitions)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err == nil && actual != tC.expected {
+				t.Errorf("Expected partition: %d, Actual: %d", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMurmur2(t *testing.T) {
+	// The expected values are taken from the test suite of the Java client.
+	testCases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for input, expected := range testCases {
+		t.Run(input, func(t *testing.T) {
+			actual := murmur2([]byte(input))
+			if actual != expected {
+				t.Errorf("Expected hash: %d, Actual: %d", expected, actual)
+			}
+		})
+	}
+}
+
+func TestPartitionerConstructor(t *testing.T) {
+	testCases := []struct {
+		title         string
+		name          string
+		key           sarama.Encoder
+		metadata      interface{}
+		expected      int32
+		expectedError string
+	}{
+		{
+			title:    "murmur2",
+			name:     Murmur2Partitioner,
+			key:      sarama.StringEncoder("foobar"),
+			expected: (-790332482 & 0x7fffffff) % 10,
+		},
+		{
+			title:    "murmur2 with requested partition",
+			name:     Murmur2Partitioner,
+			key:      sarama.StringEncoder("foobar"),
+			metadata: &messageMetadata{partition: &partitionRequest{partition: 7, strict: true}},
+			expected: 7,
+		},
+		{
+			title:    "round robin",
+			name:     RoundRobinPartitioner,
+			key:      sarama.StringEncoder("foobar"),
+			expected: 0,
+		},
+		{
+			title:    "hash",
+			name:     HashPartitioner,
+			key:      sarama.StringEncoder("key"),
+			metadata: &messageMetadata{partition: &partitionRequest{partition: 3, strict: true}},
+			expected: 3,
+		},
+		{
+			title:         "invalid partitioner",
+			name:          "sticky",
+			expectedError: `invalid partitioner "sticky". The partitioner must be one of hash, murmur2, random, roundrobin`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			constructor, err := partitionerConstructor(tC.name)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			actual, err := constructor("topic").Partition(&sarama.ProducerMessage{Key: tC.key, Metadata: tC.metadata}, 10)
+			if err != nil {
+				t.Fatalf("Failed to select the partition: %s", err)
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected partition: %d, Actual: %d", tC.expected, actual)
+			}
+		})
+	}
+}
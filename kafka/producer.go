@@ -1,7 +1,10 @@
 package kafka
 
 import (
-	"github.com/Shopify/sarama"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
 )
 
 // Producer represents a wrapper around Sarama producer.
@@ -27,14 +30,68 @@ func NewProducer(brokers []string, options ...Option) (*Producer, error) {
 }
 
 // Produce publishes a new message to the specified Kafka topic.
-func (p *Producer) Produce(topic string, key, value []byte) (int32, int64, error) {
+//
+// The partition will be selected based on the key, if the requested partition is negative.
+func (p *Producer) Produce(topic string, key, value []byte, headers map[string]string, partition int32) (int32, int64, error) {
+	return p.producer.SendMessage(newProducerMessage(topic, key, value, headers, partition))
+}
+
+// ProduceEvent publishes the event to the specified Kafka topic, preserving the key, the timestamp and the headers of the event.
+//
+// If preservePartition is true, the event will be published to the same partition number, as long as the partition exists on
+// the destination topic. Otherwise, the partition will be selected based on the key.
+func (p *Producer) ProduceEvent(topic string, event *Event, preservePartition bool) (int32, int64, error) {
 	message := &sarama.ProducerMessage{
-		Topic: topic,
-		Key:   sarama.ByteEncoder(key),
-		Value: sarama.ByteEncoder(value)}
+		Topic:     topic,
+		Key:       sarama.ByteEncoder(event.Key),
+		Value:     sarama.ByteEncoder(event.Value),
+		Timestamp: event.Timestamp,
+	}
+	if event.Key == nil {
+		message.Key = nil
+	}
+	if preservePartition {
+		message.Metadata = &messageMetadata{
+			partition: &partitionRequest{partition: event.Partition},
+		}
+	}
+	for _, header := range event.Headers {
+		if header != nil {
+			message.Headers = append(message.Headers, *header)
+		}
+	}
 	return p.producer.SendMessage(message)
 }
 
+// IsTransactional returns true if the producer has been initialised with a transactional ID.
+func (p *Producer) IsTransactional() bool {
+	return p.producer.IsTransactional()
+}
+
+// BeginTransaction starts a new transaction.
+func (p *Producer) BeginTransaction() error {
+	if err := p.producer.BeginTxn(); err != nil {
+		return fmt.Errorf("failed to begin the transaction: %w", err)
+	}
+	return nil
+}
+
+// CommitTransaction commits the ongoing transaction.
+func (p *Producer) CommitTransaction() error {
+	if err := p.producer.CommitTxn(); err != nil {
+		return fmt.Errorf("failed to commit the transaction: %w", err)
+	}
+	return nil
+}
+
+// AbortTransaction aborts the ongoing transaction.
+func (p *Producer) AbortTransaction() error {
+	if err := p.producer.AbortTxn(); err != nil {
+		return fmt.Errorf("failed to abort the transaction: %w", err)
+	}
+	return nil
+}
+
 // Close closes the producer.
 func (p *Producer) Close() error {
 	if p.producer != nil {
@@ -42,3 +99,38 @@ func (p *Producer) Close() error {
 	}
 	return nil
 }
+
+func newProducerMessage(topic string, key, value []byte, headers map[string]string, partition int32) *sarama.ProducerMessage {
+	message := &sarama.ProducerMessage{
+		Topic:    topic,
+		Key:      sarama.ByteEncoder(key),
+		Value:    sarama.ByteEncoder(value),
+		Headers:  toRecordHeaders(headers),
+		Metadata: &messageMetadata{},
+	}
+	if partition >= 0 {
+		message.Metadata = &messageMetadata{
+			partition: &partitionRequest{partition: partition, strict: true},
+		}
+	}
+	return message
+}
+
+func toRecordHeaders(headers map[string]string) []sarama.RecordHeader {
+	if len(headers) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]sarama.RecordHeader, len(keys))
+	for i, key := range keys {
+		result[i] = sarama.RecordHeader{
+			Key:   []byte(key),
+			Value: []byte(headers[key]),
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	// AcksAll waits for all the in-sync replicas to commit the message.
+	AcksAll = "all"
+	// AcksLeader waits for the leader to commit the message.
+	AcksLeader = "leader"
+	// AcksNone does not wait for any acknowledgements.
+	AcksNone = "none"
+)
+
+const (
+	// HashPartitioner selects the partition using the FNV-1a hash of the key.
+	HashPartitioner = "hash"
+	// Murmur2Partitioner selects the partition using the murmur2 hash of the key, compatible with the Java client.
+	Murmur2Partitioner = "murmur2"
+	// RandomPartitioner selects a random partition for each message.
+	RandomPartitioner = "random"
+	// RoundRobinPartitioner distributes the messages across the partitions in a round robin fashion.
+	RoundRobinPartitioner = "roundrobin"
+)
+
+// Partitioners the supported partitioners.
+var Partitioners = []string{HashPartitioner, Murmur2Partitioner, RandomPartitioner, RoundRobinPartitioner}
+
+// CompressionCodecs the supported compression codecs.
+var CompressionCodecs = []string{"none", "gzip", "snappy", "lz4", "zstd"}
+
+// ProducerSettings holds the producer configuration settings.
+type ProducerSettings struct {
+	// BatchSize the number of messages which triggers a flush. Zero means the default value.
+	BatchSize int
+	// Linger the maximum amount of time to wait before sending a batch. Zero means the default value.
+	Linger time.Duration
+	// Compression the compression codec (eg. none, gzip, snappy, lz4 or zstd).
+	Compression string
+	// Acks the required acknowledgements (all, leader or none).
+	Acks string
+	// MaxInFlight the maximum number of in-flight requests per broker connection. Zero means the default value.
+	MaxInFlight int
+	// Partitioner the partitioner to select the partitions of the messages which are not explicitly assigned to a partition.
+	Partitioner string
+	// Idempotent if true, the producer ensures that exactly one copy of each message is written to the log.
+	Idempotent bool
+	// TransactionalID the transactional ID of the producer. Setting the transactional ID enables idempotence.
+	TransactionalID string
+}
+
+func (s *ProducerSettings) apply(config *sarama.Config) error {
+	if s.BatchSize < 0 {
+		return errors.New("the batch size cannot be negative")
+	}
+	if s.Linger < 0 {
+		return errors.New("the linger time cannot be negative")
+	}
+	if s.MaxInFlight < 0 {
+		return errors.New("the maximum number of in-flight requests cannot be negative")
+	}
+	if s.BatchSize > 0 {
+		config.Producer.Flush.Messages = s.BatchSize
+	}
+	if s.Linger > 0 {
+		config.Producer.Flush.Frequency = s.Linger
+	}
+	if s.MaxInFlight > 0 {
+		config.Net.MaxOpenRequests = s.MaxInFlight
+	}
+	if s.Compression != "" {
+		var codec sarama.CompressionCodec
+		if err := codec.UnmarshalText([]byte(s.Compression)); err != nil {
+			return fmt.Errorf("invalid compression codec %q", s.Compression)
+		}
+		config.Producer.Compression = codec
+	}
+	if s.Partitioner != "" {
+		constructor, err := partitionerConstructor(s.Partitioner)
+		if err != nil {
+			return err
+		}
+		config.Producer.Partitioner = constructor
+	}
+	switch s.Acks {
+	case "", AcksAll:
+		config.Producer.RequiredAcks = sarama.WaitForAll
+	case AcksLeader:
+		config.Producer.RequiredAcks = sarama.WaitForLocal
+	case AcksNone:
+		config.Producer.RequiredAcks = sarama.NoResponse
+	default:
+		return fmt.Errorf("invalid acks value %q. The acks must be one of %s, %s or %s", s.Acks, AcksAll, AcksLeader, AcksNone)
+	}
+	if s.TransactionalID != "" {
+		config.Producer.Transaction.ID = s.TransactionalID
+		config.Producer.Idempotent = true
+	}
+	if s.Idempotent {
+		config.Producer.Idempotent = true
+	}
+	if config.Producer.Idempotent {
+		if config.Producer.RequiredAcks != sarama.WaitForAll {
+			return fmt.Errorf("the idempotent producer requires the acks to be set to %s", AcksAll)
+		}
+		// Sarama does not support more than one in-flight request per connection for idempotent producers.
+		config.Net.MaxOpenRequests = 1
+	}
+	return nil
+}
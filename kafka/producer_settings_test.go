@@ -0,0 +1,138 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestProducerSettingsApply(t *testing.T) {
+	testCases := []struct {
+		title               string
+		settings            *ProducerSettings
+		expectedMessages    int
+		expectedFrequency   time.Duration
+		expectedCompression sarama.CompressionCodec
+		expectedAcks        sarama.RequiredAcks
+		expectedInFlight    int
+		expectedIdempotent  bool
+		expectedTxnID       string
+		expectedError       string
+	}{
+		{
+			title:               "default values",
+			settings:            &ProducerSettings{},
+			expectedCompression: sarama.CompressionNone,
+			expectedAcks:        sarama.WaitForAll,
+			expectedInFlight:    5,
+		},
+		{
+			title: "custom values",
+			settings: &ProducerSettings{
+				BatchSize:   500,
+				Linger:      10 * time.Millisecond,
+				Compression: "zstd",
+				Acks:        AcksLeader,
+				MaxInFlight: 1,
+			},
+			expectedMessages:    500,
+			expectedFrequency:   10 * time.Millisecond,
+			expectedCompression: sarama.CompressionZSTD,
+			expectedAcks:        sarama.WaitForLocal,
+			expectedInFlight:    1,
+		},
+		{
+			title:               "no acks",
+			settings:            &ProducerSettings{Acks: AcksNone, Compression: "lz4"},
+			expectedCompression: sarama.CompressionLZ4,
+			expectedAcks:        sarama.NoResponse,
+			expectedInFlight:    5,
+		},
+		{
+			title:               "idempotent",
+			settings:            &ProducerSettings{Idempotent: true, MaxInFlight: 5},
+			expectedCompression: sarama.CompressionNone,
+			expectedAcks:        sarama.WaitForAll,
+			expectedInFlight:    1,
+			expectedIdempotent:  true,
+		},
+		{
+			title:               "transactional",
+			settings:            &ProducerSettings{TransactionalID: "loader"},
+			expectedCompression: sarama.CompressionNone,
+			expectedAcks:        sarama.WaitForAll,
+			expectedInFlight:    1,
+			expectedIdempotent:  true,
+			expectedTxnID:       "loader",
+		},
+		{
+			title:         "idempotent without all acks",
+			settings:      &ProducerSettings{Idempotent: true, Acks: AcksLeader},
+			expectedError: "the idempotent producer requires the acks to be set to all",
+		},
+		{
+			title:         "invalid compression",
+			settings:      &ProducerSettings{Compression: "brotli"},
+			expectedError: `invalid compression codec "brotli"`,
+		},
+		{
+			title:         "invalid acks",
+			settings:      &ProducerSettings{Acks: "some"},
+			expectedError: `invalid acks value "some". The acks must be one of all, leader or none`,
+		},
+		{
+			title:         "invalid partitioner",
+			settings:      &ProducerSettings{Partitioner: "sticky"},
+			expectedError: `invalid partitioner "sticky"`,
+		},
+		{
+			title:         "negative batch size",
+			settings:      &ProducerSettings{BatchSize: -1},
+			expectedError: "the batch size cannot be negative",
+		},
+		{
+			title:         "negative linger",
+			settings:      &ProducerSettings{Linger: -time.Second},
+			expectedError: "the linger time cannot be negative",
+		},
+		{
+			title:         "negative max in-flight",
+			settings:      &ProducerSettings{MaxInFlight: -1},
+			expectedError: "the maximum number of in-flight requests cannot be negative",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			config := sarama.NewConfig()
+			err := tC.settings.apply(config)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if config.Producer.Flush.Messages != tC.expectedMessages {
+				t.Errorf("Expected flush messages: %d, Actual: %d", tC.expectedMessages, config.Producer.Flush.Messages)
+			}
+			if config.Producer.Flush.Frequency != tC.expectedFrequency {
+				t.Errorf("Expected flush frequency: %s, Actual: %s", tC.expectedFrequency, config.Producer.Flush.Frequency)
+			}
+			if config.Producer.Compression != tC.expectedCompression {
+				t.Errorf("Expected compression: %s, Actual: %s", tC.expectedCompression, config.Producer.Compression)
+			}
+			if config.Producer.RequiredAcks != tC.expectedAcks {
+				t.Errorf("Expected acks: %d, Actual: %d", tC.expectedAcks, config.Producer.RequiredAcks)
+			}
+			if config.Net.MaxOpenRequests != tC.expectedInFlight {
+				t.Errorf("Expected max in-flight requests: %d, Actual: %d", tC.expectedInFlight, config.Net.MaxOpenRequests)
+			}
+			if config.Producer.Idempotent != tC.expectedIdempotent {
+				t.Errorf("Expected idempotent: %v, Actual: %v", tC.expectedIdempotent, config.Producer.Idempotent)
+			}
+			if config.Producer.Transaction.ID != tC.expectedTxnID {
+				t.Errorf("Expected transactional ID: %q, Actual: %q", tC.expectedTxnID, config.Producer.Transaction.ID)
+			}
+		})
+	}
+}
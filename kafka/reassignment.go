@@ -0,0 +1,192 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/IBM/sarama"
+)
+
+const reassignmentPlanVersion = 1
+
+// PartitionReassignment represents the replica assignment of a topic partition.
+type PartitionReassignment struct {
+	Topic     string  `json:"topic"`
+	Partition int32   `json:"partition"`
+	Replicas  []int32 `json:"replicas"`
+}
+
+// ReassignmentPlan represents a partition reassignment plan.
+//
+// The plan is compatible with the Json format used by the kafka-reassign-partitions tool.
+type ReassignmentPlan struct {
+	Version    int                      `json:"version"`
+	Partitions []*PartitionReassignment `json:"partitions"`
+}
+
+// ReassignmentStatus represents the status of an ongoing partition reassignment.
+type ReassignmentStatus struct {
+	Topic     string  `json:"topic"`
+	Partition int32   `json:"partition"`
+	Replicas  []int32 `json:"replicas"`
+	Adding    []int32 `json:"adding_replicas"`
+	Removing  []int32 `json:"removing_replicas"`
+}
+
+// ParseReassignmentPlan parses and validates the Json representation of a reassignment plan.
+func ParseReassignmentPlan(data []byte) (*ReassignmentPlan, error) {
+	plan := &ReassignmentPlan{}
+	err := json.Unmarshal(data, plan)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reassignment plan: %w", err)
+	}
+	if plan.Version != reassignmentPlanVersion {
+		return nil, fmt.Errorf("unsupported reassignment plan version %d", plan.Version)
+	}
+	if len(plan.Partitions) == 0 {
+		return nil, errors.New("the reassignment plan is empty")
+	}
+	seen := make(map[string]map[int32]bool)
+	for _, p := range plan.Partitions {
+		if p == nil || p.Topic == "" {
+			return nil, errors.New("the topic name of the partitions in the reassignment plan cannot be empty")
+		}
+		if p.Partition < 0 {
+			return nil, fmt.Errorf("invalid partition %d of %s topic in the reassignment plan", p.Partition, p.Topic)
+		}
+		if seen[p.Topic][p.Partition] {
+			return nil, fmt.Errorf("partition %d of %s topic has been reassigned more than once", p.Partition, p.Topic)
+		}
+		if _, ok := seen[p.Topic]; !ok {
+			seen[p.Topic] = make(map[int32]bool)
+		}
+		seen[p.Topic][p.Partition] = true
+		if len(p.Replicas) == 0 {
+			return nil, fmt.Errorf("no replicas have been assigned to partition %d of %s topic", p.Partition, p.Topic)
+		}
+		replicas := make(map[int32]bool)
+		for _, replica := range p.Replicas {
+			if replicas[replica] {
+				return nil, fmt.Errorf("broker %d has been assigned to partition %d of %s topic more than once", replica, p.Partition, p.Topic)
+			}
+			replicas[replica] = true
+		}
+	}
+	return plan, nil
+}
+
+// Topics returns the sorted list of the topics in the plan.
+func (p *ReassignmentPlan) Topics() []string {
+	unique := make(map[string]bool)
+	for _, partition := range p.Partitions {
+		unique[partition.Topic] = true
+	}
+	topics := make([]string, 0, len(unique))
+	for topic := range unique {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Replicas returns the replicas assigned to the specified partition, or nil if the partition is not in the plan.
+func (p *ReassignmentPlan) Replicas(topic string, partition int32) []int32 {
+	for _, r := range p.Partitions {
+		if r.Topic == topic && r.Partition == partition {
+			return r.Replicas
+		}
+	}
+	return nil
+}
+
+func (p *ReassignmentPlan) brokers() []int32 {
+	unique := make(map[int32]bool)
+	result := make([]int32, 0)
+	for _, partition := range p.Partitions {
+		for _, id := range partition.Replicas {
+			if !unique[id] {
+				unique[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// targetReplicas returns the replicas which the partition will be assigned to once the ongoing reassignment is complete.
+//
+// The replica list of a partition which is being reassigned is the union of the original and the new replicas.
+func targetReplicas(status *sarama.PartitionReplicaReassignmentsStatus) []int32 {
+	removing := make(map[int32]bool)
+	for _, id := range status.RemovingReplicas {
+		removing[id] = true
+	}
+	result := make([]int32, 0, len(status.Replicas))
+	for _, id := range status.Replicas {
+		if !removing[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func newReassignmentPlan() *ReassignmentPlan {
+	return &ReassignmentPlan{
+		Version:    reassignmentPlanVersion,
+		Partitions: make([]*PartitionReassignment, 0),
+	}
+}
+
+func (p *ReassignmentPlan) sort() {
+	sort.Slice(p.Partitions, func(i, j int) bool {
+		if p.Partitions[i].Topic != p.Partitions[j].Topic {
+			return p.Partitions[i].Topic < p.Partitions[j].Topic
+		}
+		return p.Partitions[i].Partition < p.Partitions[j].Partition
+	})
+}
+
+// balancedPlan distributes the replicas of the current plan evenly across the specified brokers.
+//
+// The replication factor of each partition stays the same. The preferred leaders (the first replica) are assigned
+// to the brokers in a round robin fashion, followed by the next brokers in the list.
+func balancedPlan(current *ReassignmentPlan, brokerIDs []int32) (*ReassignmentPlan, error) {
+	if len(brokerIDs) == 0 {
+		return nil, errors.New("no brokers have been specified")
+	}
+	brokers := make([]int32, len(brokerIDs))
+	copy(brokers, brokerIDs)
+	sort.Slice(brokers, func(i, j int) bool {
+		return brokers[i] < brokers[j]
+	})
+	for i := 1; i < len(brokers); i++ {
+		if brokers[i] == brokers[i-1] {
+			return nil, fmt.Errorf("broker %d has been specified more than once", brokers[i])
+		}
+	}
+
+	current.sort()
+	proposed := newReassignmentPlan()
+	for i, p := range current.Partitions {
+		replicationFactor := len(p.Replicas)
+		if replicationFactor > len(brokers) {
+			return nil, fmt.Errorf("the replication factor of partition %d of %s topic (%d) is greater than the number of brokers (%d)",
+				p.Partition,
+				p.Topic,
+				replicationFactor,
+				len(brokers))
+		}
+		replicas := make([]int32, replicationFactor)
+		for j := range replicas {
+			replicas[j] = brokers[(i+j)%len(brokers)]
+		}
+		proposed.Partitions = append(proposed.Partitions, &PartitionReassignment{
+			Topic:     p.Topic,
+			Partition: p.Partition,
+			Replicas:  replicas,
+		})
+	}
+	return proposed, nil
+}
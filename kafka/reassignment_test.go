@@ -0,0 +1,255 @@
+package kafka
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/IBM/sarama"
+
+	"github.com/xitonix/trubka/internal"
+)
+
+func TestParseReassignmentPlan(t *testing.T) {
+	testCases := []struct {
+		title         string
+		input         string
+		expected      *ReassignmentPlan
+		expectedError string
+	}{
+		{
+			title: "valid plan",
+			input: `{"version":1,"partitions":[{"topic":"events","partition":0,"replicas":[1,2]},{"topic":"events","partition":1,"replicas":[2,3]}]}`,
+			expected: &ReassignmentPlan{
+				Version: 1,
+				Partitions: []*PartitionReassignment{
+					{Topic: "events", Partition: 0, Replicas: []int32{1, 2}},
+					{Topic: "events", Partition: 1, Replicas: []int32{2, 3}},
+				},
+			},
+		},
+		{
+			title:         "invalid json",
+			input:         `{"version":`,
+			expectedError: "invalid reassignment plan: unexpected end of JSON input",
+		},
+		{
+			title:         "unsupported version",
+			input:         `{"version":2,"partitions":[{"topic":"events","partition":0,"replicas":[1]}]}`,
+			expectedError: "unsupported reassignment plan version 2",
+		},
+		{
+			title:         "empty plan",
+			input:         `{"version":1,"partitions":[]}`,
+			expectedError: "the reassignment plan is empty",
+		},
+		{
+			title:         "empty topic",
+			input:         `{"version":1,"partitions":[{"partition":0,"replicas":[1]}]}`,
+			expectedError: "the topic name of the partitions in the reassignment plan cannot be empty",
+		},
+		{
+			title:         "negative partition",
+			input:         `{"version":1,"partitions":[{"topic":"events","partition":-1,"replicas":[1]}]}`,
+			expectedError: "invalid partition -1 of events topic in the reassignment plan",
+		},
+		{
+			title:         "duplicate partition",
+			input:         `{"version":1,"partitions":[{"topic":"events","partition":0,"replicas":[1]},{"topic":"events","partition":0,"replicas":[2]}]}`,
+			expectedError: "partition 0 of events topic has been reassigned more than once",
+		},
+		{
+			title:         "no replicas",
+			input:         `{"version":1,"partitions":[{"topic":"events","partition":0,"replicas":[]}]}`,
+			expectedError: "no replicas have been assigned to partition 0 of events topic",
+		},
+		{
+			title:         "duplicate replicas",
+			input:         `{"version":1,"partitions":[{"topic":"events","partition":0,"replicas":[1,1]}]}`,
+			expectedError: "broker 1 has been assigned to partition 0 of events topic more than once",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			actual, err := ParseReassignmentPlan([]byte(tC.input))
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(actual, tC.expected) {
+				t.Errorf("Expected plan: %+v, Actual: %+v", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBalancedPlan(t *testing.T) {
+	testCases := []struct {
+		title         string
+		current       []*PartitionReassignment
+		brokers       []int32
+		expected      []*PartitionReassignment
+		expectedError string
+	}{
+		{
+			title: "single replica",
+			current: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1}},
+				{Topic: "events", Partition: 1, Replicas: []int32{1}},
+				{Topic: "events", Partition: 2, Replicas: []int32{1}},
+			},
+			brokers: []int32{3, 2},
+			expected: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{2}},
+				{Topic: "events", Partition: 1, Replicas: []int32{3}},
+				{Topic: "events", Partition: 2, Replicas: []int32{2}},
+			},
+		},
+		{
+			title: "multiple topics and replicas",
+			current: []*PartitionReassignment{
+				{Topic: "orders", Partition: 0, Replicas: []int32{1, 2}},
+				{Topic: "events", Partition: 1, Replicas: []int32{1, 2, 3}},
+				{Topic: "events", Partition: 0, Replicas: []int32{1, 2, 3}},
+			},
+			brokers: []int32{1, 2, 3, 4},
+			expected: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1, 2, 3}},
+				{Topic: "events", Partition: 1, Replicas: []int32{2, 3, 4}},
+				{Topic: "orders", Partition: 0, Replicas: []int32{3, 4}},
+			},
+		},
+		{
+			title: "replication factor greater than the number of brokers",
+			current: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1, 2, 3}},
+			},
+			brokers:       []int32{1, 2},
+			expectedError: "the replication factor of partition 0 of events topic (3) is greater than the number of brokers (2)",
+		},
+		{
+			title: "no brokers",
+			current: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1}},
+			},
+			expectedError: "no brokers have been specified",
+		},
+		{
+			title: "duplicate brokers",
+			current: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1}},
+			},
+			brokers:       []int32{1, 2, 1},
+			expectedError: "broker 1 has been specified more than once",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			current := newReassignmentPlan()
+			current.Partitions = tC.current
+			actual, err := balancedPlan(current, tC.brokers)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if actual.Version != reassignmentPlanVersion {
+				t.Errorf("Expected version: %d, Actual: %d", reassignmentPlanVersion, actual.Version)
+			}
+			if !reflect.DeepEqual(actual.Partitions, tC.expected) {
+				t.Errorf("Expected partitions: %v, Actual: %v", tC.expected, actual.Partitions)
+			}
+		})
+	}
+}
+
+func TestExecuteReassignmentPlan(t *testing.T) {
+	testCases := []struct {
+		title         string
+		replicas      map[string][][]int32
+		ongoing       map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus
+		plan          *ReassignmentPlan
+		expected      map[string][][]int32
+		expectedError string
+	}{
+		{
+			title:    "partitions outside the plan keep their replicas",
+			replicas: map[string][][]int32{"events": {{1, 2}, {2, 3}, {3, 1}}},
+			plan: &ReassignmentPlan{Partitions: []*PartitionReassignment{
+				{Topic: "events", Partition: 1, Replicas: []int32{3, 1}},
+			}},
+			expected: map[string][][]int32{"events": {{1, 2}, {3, 1}, {3, 1}}},
+		},
+		{
+			title: "partitions outside the plan keep the target of their ongoing reassignment",
+			// The metadata of a partition which is being reassigned contains both the adding and the removing replicas.
+			replicas: map[string][][]int32{"events": {{1, 2}, {2, 3}, {1, 2, 3}}},
+			ongoing: map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus{
+				"events": {
+					2: {Replicas: []int32{1, 2, 3}, AddingReplicas: []int32{3}, RemovingReplicas: []int32{2}},
+				},
+			},
+			plan: &ReassignmentPlan{Partitions: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{2, 1}},
+			}},
+			expected: map[string][][]int32{"events": {{2, 1}, {2, 3}, {1, 3}}},
+		},
+		{
+			title:    "the plan overrides the ongoing reassignment",
+			replicas: map[string][][]int32{"events": {{1, 2, 3}}},
+			ongoing: map[string]map[int32]*sarama.PartitionReplicaReassignmentsStatus{
+				"events": {
+					0: {Replicas: []int32{1, 2, 3}, AddingReplicas: []int32{3}, RemovingReplicas: []int32{2}},
+				},
+			},
+			plan: &ReassignmentPlan{Partitions: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1, 2}},
+			}},
+			expected: map[string][][]int32{"events": {{1, 2}}},
+		},
+		{
+			title:    "partition not found",
+			replicas: map[string][][]int32{"events": {{1, 2}}},
+			plan: &ReassignmentPlan{Partitions: []*PartitionReassignment{
+				{Topic: "events", Partition: 1, Replicas: []int32{1, 2}},
+			}},
+			expectedError: "partition 1 of events topic not found",
+		},
+		{
+			title:    "broker not found",
+			replicas: map[string][][]int32{"events": {{1, 2}}},
+			plan: &ReassignmentPlan{Partitions: []*PartitionReassignment{
+				{Topic: "events", Partition: 0, Replicas: []int32{1, 4}},
+			}},
+			expectedError: "broker 4 not found",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			admin := &clusterAdminMock{reassignments: tC.ongoing}
+			m := &Manager{
+				client: &metadataClientMock{replicas: tC.replicas},
+				admin:  admin,
+				serversByID: map[int32]*Broker{
+					1: {ID: 1},
+					2: {ID: 2},
+					3: {ID: 3},
+				},
+				Logger: internal.NewLogger(internal.Forced),
+			}
+			err := m.ExecuteReassignmentPlan(context.Background(), tC.plan)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(admin.submitted, tC.expected) {
+				t.Errorf("Expected assignments: %v, Actual: %v", tC.expected, admin.submitted)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package kafka
+
+// RecordsDeletion represents the records to be deleted from the beginning of a partition.
+type RecordsDeletion struct {
+	// Partition the partition number.
+	Partition int32 `json:"partition"`
+	// Earliest the current log start offset of the partition.
+	Earliest int64 `json:"earliest_offset"`
+	// Latest the latest available offset of the partition.
+	Latest int64 `json:"latest_offset"`
+	// Before the new log start offset of the partition. All the records before this offset will be deleted.
+	Before int64 `json:"before_offset"`
+}
+
+// Count returns the number of records to be deleted.
+func (r RecordsDeletion) Count() int64 {
+	if r.Before > r.Earliest {
+		return r.Before - r.Earliest
+	}
+	return 0
+}
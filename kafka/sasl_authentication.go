@@ -3,10 +3,11 @@ package kafka
 import (
 	"crypto/sha256"
 	"crypto/sha512"
+	"errors"
 	"hash"
 	"strings"
 
-	"github.com/Shopify/sarama"
+	"github.com/IBM/sarama"
 )
 
 const (
@@ -18,45 +19,170 @@ const (
 	SASLMechanismSCRAM256 = "scram-sha-256"
 	// SASLMechanismSCRAM512 sha-512 authentication mode.
 	SASLMechanismSCRAM512 = "scram-sha-512"
+	// SASLMechanismOAuth OAuth 2.0 bearer token authentication mode.
+	SASLMechanismOAuth = "oauthbearer"
+	// SASLMechanismGSSAPI Kerberos authentication mode.
+	SASLMechanismGSSAPI = "gssapi"
 )
 
+// DefaultKerberosServiceName the default Kerberos service name of the Kafka brokers.
+const DefaultKerberosServiceName = "kafka"
+
+// KerberosSettings holds the GSSAPI (Kerberos) authentication settings.
+type KerberosSettings struct {
+	// ConfigPath the path to the krb5.conf file.
+	ConfigPath string
+	// KeyTabPath the path to the keytab file. The password will be used to authenticate if not set.
+	KeyTabPath string
+	// ServiceName the Kerberos service name of the brokers.
+	ServiceName string
+	// Realm the Kerberos realm.
+	Realm string
+	// DisablePAFXFAST disables the FAST negotiation (PA-FX-FAST), which is not supported by some KDCs.
+	DisablePAFXFAST bool
+}
+
+// SASLOption configures the mechanism specific SASL authentication settings.
+type SASLOption func(s *sasl)
+
+// WithOAuthToken sets the static token of the OAUTHBEARER mechanism.
+func WithOAuthToken(token string) SASLOption {
+	return func(s *sasl) {
+		s.tokenProvider = staticToken(token)
+	}
+}
+
+// WithOAuthTokenFile sets the file to read the token of the OAUTHBEARER mechanism from.
+//
+// The file will be read every time a new connection is authenticated.
+func WithOAuthTokenFile(path string) SASLOption {
+	return func(s *sasl) {
+		s.tokenProvider = tokenFile(path)
+	}
+}
+
+// WithOAuthTokenEndpoint requests the tokens of the OAUTHBEARER mechanism from the token endpoint, using the
+// client credentials grant. The SASL username and password will be used as the client ID and secret.
+func WithOAuthTokenEndpoint(endpoint string, scopes ...string) SASLOption {
+	return func(s *sasl) {
+		s.tokenEndpoint = endpoint
+		s.scopes = scopes
+	}
+}
+
+// WithKerberos sets the settings of the GSSAPI mechanism.
+func WithKerberos(settings KerberosSettings) SASLOption {
+	return func(s *sasl) {
+		s.kerberos = settings
+	}
+}
+
 type sasl struct {
-	mechanism sarama.SASLMechanism
-	username  string
-	password  string
-	client    func() sarama.SCRAMClient
-	version   int16
+	mechanism     sarama.SASLMechanism
+	username      string
+	password      SASLPassword
+	client        func() sarama.SCRAMClient
+	version       int16
+	tokenProvider sarama.AccessTokenProvider
+	tokenEndpoint string
+	scopes        []string
+	kerberos      KerberosSettings
 }
 
 // This will return nil if the mechanism is not valid.
-func newSASL(mechanism, username, password string, version SASLHandshakeVersion) *sasl {
+func newSASL(mechanism, username string, password SASLPassword, version SASLHandshakeVersion, options ...SASLOption) *sasl {
+	s := &sasl{
+		username: username,
+		password: password,
+		version:  version.toSaramaVersion(),
+	}
 	switch strings.ToLower(mechanism) {
 	case SASLMechanismPlain:
-		return &sasl{
-			mechanism: sarama.SASLTypePlaintext,
-			username:  username,
-			password:  password,
-			version:   version.toSaramaVersion(),
-		}
+		s.mechanism = sarama.SASLTypePlaintext
 	case SASLMechanismSCRAM256:
 		hashed := func() hash.Hash { return sha256.New() }
-		return &sasl{
-			client:    func() sarama.SCRAMClient { return &xdgSCRAMClient{HashGeneratorFcn: hashed} },
-			mechanism: sarama.SASLTypeSCRAMSHA256,
-			username:  username,
-			password:  password,
-			version:   version.toSaramaVersion(),
-		}
+		s.client = func() sarama.SCRAMClient { return &xdgSCRAMClient{HashGeneratorFcn: hashed} }
+		s.mechanism = sarama.SASLTypeSCRAMSHA256
 	case SASLMechanismSCRAM512:
 		hashed := func() hash.Hash { return sha512.New() }
-		return &sasl{
-			client:    func() sarama.SCRAMClient { return &xdgSCRAMClient{HashGeneratorFcn: hashed} },
-			mechanism: sarama.SASLTypeSCRAMSHA512,
-			username:  username,
-			password:  password,
-			version:   version.toSaramaVersion(),
+		s.client = func() sarama.SCRAMClient { return &xdgSCRAMClient{HashGeneratorFcn: hashed} }
+		s.mechanism = sarama.SASLTypeSCRAMSHA512
+	case SASLMechanismOAuth:
+		s.mechanism = sarama.SASLTypeOAuth
+	case SASLMechanismGSSAPI:
+		s.mechanism = sarama.SASLTypeGSSAPI
+	default:
+		return nil
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// apply enables SASL authentication on the client configuration.
+//
+// The password will only be read if it is required by the mechanism.
+func (s *sasl) apply(config *sarama.Config) error {
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Version = s.version
+	config.Net.SASL.Mechanism = s.mechanism
+	config.Net.SASL.User = s.username
+
+	switch s.mechanism {
+	case sarama.SASLTypeOAuth:
+		switch {
+		case s.tokenProvider != nil:
+			config.Net.SASL.TokenProvider = s.tokenProvider
+		case s.tokenEndpoint != "":
+			if s.username == "" {
+				return errors.New("the client ID (SASL username) is required to request OAuth tokens from the token endpoint")
+			}
+			config.Net.SASL.TokenProvider = newClientCredentials(s.tokenEndpoint, s.username, s.password, s.scopes)
+		default:
+			return errors.New("the OAuth token, the token file or the token endpoint must be provided to use the OAUTHBEARER mechanism")
 		}
+		return nil
+	case sarama.SASLTypeGSSAPI:
+		if s.username == "" {
+			return errors.New("the Kerberos principal (SASL username) is required to use the GSSAPI mechanism")
+		}
+		gssapi := sarama.GSSAPIConfig{
+			AuthType:           sarama.KRB5_USER_AUTH,
+			KerberosConfigPath: s.kerberos.ConfigPath,
+			KeyTabPath:         s.kerberos.KeyTabPath,
+			ServiceName:        s.kerberos.ServiceName,
+			Username:           s.username,
+			Realm:              s.kerberos.Realm,
+			DisablePAFXFAST:    s.kerberos.DisablePAFXFAST,
+		}
+		if gssapi.ServiceName == "" {
+			gssapi.ServiceName = DefaultKerberosServiceName
+		}
+		if gssapi.KerberosConfigPath == "" {
+			return errors.New("the path to the Kerberos configuration file (krb5.conf) is required to use the GSSAPI mechanism")
+		}
+		if gssapi.Realm == "" {
+			return errors.New("the Kerberos realm is required to use the GSSAPI mechanism")
+		}
+		if gssapi.KeyTabPath != "" {
+			gssapi.AuthType = sarama.KRB5_KEYTAB_AUTH
+		} else {
+			password, err := s.password()
+			if err != nil {
+				return err
+			}
+			gssapi.Password = password
+		}
+		config.Net.SASL.GSSAPI = gssapi
+		return nil
 	default:
+		password, err := s.password()
+		if err != nil {
+			return err
+		}
+		config.Net.SASL.Password = password
+		config.Net.SASL.SCRAMClientGeneratorFunc = s.client
 		return nil
 	}
 }
@@ -0,0 +1,242 @@
+package kafka
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestSASLApply(t *testing.T) {
+	tokenFilePath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFilePath, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the token file: %s", err)
+	}
+	kerberos := KerberosSettings{
+		ConfigPath: "/etc/krb5.conf",
+		Realm:      "EXAMPLE.COM",
+	}
+
+	testCases := []struct {
+		title             string
+		mechanism         string
+		username          string
+		options           []SASLOption
+		expectedMechanism sarama.SASLMechanism
+		expectedPassword  string
+		expectedToken     string
+		expectedAuthType  int
+		expectedService   string
+		expectedError     string
+	}{
+		{
+			title:             "plain",
+			mechanism:         SASLMechanismPlain,
+			username:          "user",
+			expectedMechanism: sarama.SASLTypePlaintext,
+			expectedPassword:  "secret",
+		},
+		{
+			title:             "scram",
+			mechanism:         SASLMechanismSCRAM512,
+			username:          "user",
+			expectedMechanism: sarama.SASLTypeSCRAMSHA512,
+			expectedPassword:  "secret",
+		},
+		{
+			title:             "oauth static token",
+			mechanism:         SASLMechanismOAuth,
+			options:           []SASLOption{WithOAuthToken("static-token")},
+			expectedMechanism: sarama.SASLTypeOAuth,
+			expectedToken:     "static-token",
+		},
+		{
+			title:             "oauth token file",
+			mechanism:         SASLMechanismOAuth,
+			options:           []SASLOption{WithOAuthTokenFile(tokenFilePath)},
+			expectedMechanism: sarama.SASLTypeOAuth,
+			expectedToken:     "file-token",
+		},
+		{
+			title:         "oauth without token",
+			mechanism:     SASLMechanismOAuth,
+			expectedError: "the OAuth token, the token file or the token endpoint must be provided",
+		},
+		{
+			title:         "oauth token endpoint without client ID",
+			mechanism:     SASLMechanismOAuth,
+			options:       []SASLOption{WithOAuthTokenEndpoint("http://localhost")},
+			expectedError: "the client ID (SASL username) is required",
+		},
+		{
+			title:             "gssapi with password",
+			mechanism:         SASLMechanismGSSAPI,
+			username:          "user",
+			options:           []SASLOption{WithKerberos(kerberos)},
+			expectedMechanism: sarama.SASLTypeGSSAPI,
+			expectedPassword:  "secret",
+			expectedAuthType:  sarama.KRB5_USER_AUTH,
+			expectedService:   DefaultKerberosServiceName,
+		},
+		{
+			title:     "gssapi with keytab",
+			mechanism: SASLMechanismGSSAPI,
+			username:  "user",
+			options: []SASLOption{WithKerberos(KerberosSettings{
+				ConfigPath:  "/etc/krb5.conf",
+				KeyTabPath:  "/etc/user.keytab",
+				ServiceName: "brokers",
+				Realm:       "EXAMPLE.COM",
+			})},
+			expectedMechanism: sarama.SASLTypeGSSAPI,
+			expectedAuthType:  sarama.KRB5_KEYTAB_AUTH,
+			expectedService:   "brokers",
+		},
+		{
+			title:         "gssapi without principal",
+			mechanism:     SASLMechanismGSSAPI,
+			options:       []SASLOption{WithKerberos(kerberos)},
+			expectedError: "the Kerberos principal (SASL username) is required",
+		},
+		{
+			title:         "gssapi without kerberos config",
+			mechanism:     SASLMechanismGSSAPI,
+			username:      "user",
+			options:       []SASLOption{WithKerberos(KerberosSettings{Realm: "EXAMPLE.COM"})},
+			expectedError: "the path to the Kerberos configuration file (krb5.conf) is required",
+		},
+		{
+			title:         "gssapi without realm",
+			mechanism:     SASLMechanismGSSAPI,
+			username:      "user",
+			options:       []SASLOption{WithKerberos(KerberosSettings{ConfigPath: "/etc/krb5.conf"})},
+			expectedError: "the Kerberos realm is required",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			var passwordRead bool
+			password := func() (string, error) {
+				passwordRead = true
+				return "secret", nil
+			}
+			s := newSASL(tC.mechanism, tC.username, password, SASLHandshakeV1, tC.options...)
+			if s == nil {
+				t.Fatalf("Expected the %s mechanism to be supported", tC.mechanism)
+			}
+			config := sarama.NewConfig()
+			err := s.apply(config)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if config.Net.SASL.Mechanism != tC.expectedMechanism {
+				t.Errorf("Expected mechanism: %s, Actual: %s", tC.expectedMechanism, config.Net.SASL.Mechanism)
+			}
+			if passwordRead != (tC.expectedPassword != "") {
+				t.Errorf("Expected the password to be read: %v, Actual: %v", tC.expectedPassword != "", passwordRead)
+			}
+			actualPassword := config.Net.SASL.Password
+			if tC.mechanism == SASLMechanismGSSAPI {
+				actualPassword = config.Net.SASL.GSSAPI.Password
+				if config.Net.SASL.GSSAPI.AuthType != tC.expectedAuthType {
+					t.Errorf("Expected auth type: %d, Actual: %d", tC.expectedAuthType, config.Net.SASL.GSSAPI.AuthType)
+				}
+				if config.Net.SASL.GSSAPI.ServiceName != tC.expectedService {
+					t.Errorf("Expected service name: %s, Actual: %s", tC.expectedService, config.Net.SASL.GSSAPI.ServiceName)
+				}
+			}
+			if actualPassword != tC.expectedPassword {
+				t.Errorf("Expected password: %q, Actual: %q", tC.expectedPassword, actualPassword)
+			}
+			if tC.expectedToken != "" {
+				token, err := config.Net.SASL.TokenProvider.Token()
+				if err != nil {
+					t.Fatalf("Failed to get the token: %s", err)
+				}
+				if token.Token != tC.expectedToken {
+					t.Errorf("Expected token: %q, Actual: %q", tC.expectedToken, token.Token)
+				}
+			}
+			if err := config.Validate(); err != nil {
+				t.Errorf("Invalid client configuration: %s", err)
+			}
+		})
+	}
+}
+
+func TestNewSASLInvalidMechanism(t *testing.T) {
+	for _, mechanism := range []string{SASLMechanismNone, "kerberos", ""} {
+		if s := newSASL(mechanism, "user", StaticPassword("secret"), SASLHandshakeV1); s != nil {
+			t.Errorf("Expected the %q mechanism to be rejected", mechanism)
+		}
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&requests, 1)
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != "kafka read" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "unexpected form %v", r.Form)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// The tokens issued after the first one expire immediately, considering the expiry margin.
+		expiresIn := 3600
+		if count > 1 {
+			expiresIn = 1
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, count, expiresIn)
+	}))
+	defer server.Close()
+
+	provider := newClientCredentials(server.URL, "client", StaticPassword("secret"), []string{"kafka", "read"})
+	expected := []string{"token-1", "token-1"}
+	for i, exp := range expected {
+		token, err := provider.Token()
+		if err != nil {
+			t.Fatalf("Failed to get the token: %s", err)
+		}
+		if token.Token != exp {
+			t.Errorf("Request %d: Expected token: %s, Actual: %s", i, exp, token.Token)
+		}
+	}
+
+	// Expire the cached token.
+	provider.expiry = provider.expiry.Add(-2 * time.Hour)
+	for _, exp := range []string{"token-2", "token-3"} {
+		token, err := provider.Token()
+		if err != nil {
+			t.Fatalf("Failed to get the token: %s", err)
+		}
+		if token.Token != exp {
+			t.Errorf("Expected token: %s, Actual: %s", exp, token.Token)
+		}
+	}
+
+	invalid := newClientCredentials(server.URL, "client", StaticPassword("wrong"), []string{"kafka", "read"})
+	_, err := invalid.Token()
+	if !checkError(err, "the OAuth token endpoint responded with 401 Unauthorized") {
+		t.Errorf("Expected an unauthorized error, Actual: %v", err)
+	}
+}
@@ -1,6 +1,6 @@
 package kafka
 
-import "github.com/Shopify/sarama"
+import "github.com/IBM/sarama"
 
 // SASLHandshakeVersion SASL handshake version.
 type SASLHandshakeVersion string
@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// tokenExpiryMargin the time before the actual expiry of the access tokens, after which a new token will be requested.
+const tokenExpiryMargin = 30 * time.Second
+
+type staticToken string
+
+func (s staticToken) Token() (*sarama.AccessToken, error) {
+	return &sarama.AccessToken{Token: string(s)}, nil
+}
+
+// tokenFile reads the token from the file every time a new token is requested, so that the tokens which are
+// rotated by an external process are picked up.
+type tokenFile string
+
+func (t tokenFile) Token() (*sarama.AccessToken, error) {
+	content, err := os.ReadFile(string(t))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OAuth token file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, fmt.Errorf("the OAuth token file %s is empty", string(t))
+	}
+	return &sarama.AccessToken{Token: token}, nil
+}
+
+// clientCredentials requests the access tokens from the token endpoint using the OAuth 2.0 client credentials grant.
+type clientCredentials struct {
+	mux          sync.Mutex
+	endpoint     string
+	clientID     string
+	clientSecret SASLPassword
+	scopes       []string
+	client       *http.Client
+	token        string
+	expiry       time.Time
+}
+
+func newClientCredentials(endpoint, clientID string, clientSecret SASLPassword, scopes []string) *clientCredentials {
+	return &clientCredentials{
+		endpoint:     endpoint,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Token returns the cached access token, or requests a new one if the cached token is about to expire.
+func (c *clientCredentials) Token() (*sarama.AccessToken, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.token != "" && (c.expiry.IsZero() || time.Now().Before(c.expiry)) {
+		return &sarama.AccessToken{Token: c.token}, nil
+	}
+	secret, err := c.clientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+	request, err := http.NewRequest(http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OAuth token endpoint: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(secret))
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request an OAuth token: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OAuth token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the OAuth token endpoint responded with %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid OAuth token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("the OAuth token endpoint did not return an access token")
+	}
+
+	c.token = result.AccessToken
+	c.expiry = time.Time{}
+	if result.ExpiresIn > 0 {
+		c.expiry = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - tokenExpiryMargin)
+	}
+	return &sarama.AccessToken{Token: c.token}, nil
+}
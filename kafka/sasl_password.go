@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// SASLPassword provides the SASL password on demand.
+//
+// The password is only read (or requested from the user) once, when the first Kafka client is initialised with
+// SASL authentication enabled.
+type SASLPassword func() (string, error)
+
+// StaticPassword returns the password as is.
+func StaticPassword(password string) SASLPassword {
+	return func() (string, error) {
+		return password, nil
+	}
+}
+
+// PasswordFile reads the password from the specified file. The trailing new line characters will be removed.
+func PasswordFile(path string) SASLPassword {
+	return once(func() (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the SASL password file: %w", err)
+		}
+		return trimNewLine(string(content)), nil
+	})
+}
+
+// PasswordCommand runs the command using the system shell and reads the password from its standard output.
+//
+// The standard error of the command is passed through, so that the password helpers can interact with the user.
+func PasswordCommand(command string) SASLPassword {
+	return once(func() (string, error) {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = os.Stderr
+		cmd.Stdin = os.Stdin
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("failed to run the SASL password command: %w", err)
+		}
+		return trimNewLine(stdout.String()), nil
+	})
+}
+
+// PasswordPrompt asks the user to enter the password without echoing the input.
+//
+// The method fails if the standard input is not a terminal.
+func PasswordPrompt(username string) SASLPassword {
+	return once(func() (string, error) {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return "", errors.New("the SASL password is required, but cannot be prompted for as the standard input is not a terminal")
+		}
+		fmt.Fprintf(os.Stderr, "SASL password for %s: ", username)
+		password, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the SASL password: %w", err)
+		}
+		return string(password), nil
+	})
+}
+
+func once(password SASLPassword) SASLPassword {
+	var (
+		o      sync.Once
+		result string
+		err    error
+	)
+	return func() (string, error) {
+		o.Do(func() {
+			result, err = password()
+		})
+		return result, err
+	}
+}
+
+func trimNewLine(input string) string {
+	return strings.TrimRight(input, "\r\n")
+}
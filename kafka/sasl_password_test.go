@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSASLPassword(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "password")
+	if err := os.WriteFile(file, []byte("file-secret\r\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the password file: %s", err)
+	}
+
+	testCases := []struct {
+		title         string
+		password      SASLPassword
+		unixOnly      bool
+		expected      string
+		expectedError string
+	}{
+		{
+			title:    "static password",
+			password: StaticPassword("static-secret"),
+			expected: "static-secret",
+		},
+		{
+			title:    "password file",
+			password: PasswordFile(file),
+			expected: "file-secret",
+		},
+		{
+			title:         "missing password file",
+			password:      PasswordFile(filepath.Join(dir, "missing")),
+			expectedError: "failed to read the SASL password file",
+		},
+		{
+			title:    "password command",
+			password: PasswordCommand("echo command-secret"),
+			unixOnly: true,
+			expected: "command-secret",
+		},
+		{
+			title:    "password command with spaces",
+			password: PasswordCommand("printf '%s\\n' 'with spaces '"),
+			unixOnly: true,
+			expected: "with spaces ",
+		},
+		{
+			title:         "failing password command",
+			password:      PasswordCommand("exit 3"),
+			unixOnly:      true,
+			expectedError: "failed to run the SASL password command",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			if tC.unixOnly && runtime.GOOS == "windows" {
+				t.Skip("The test requires a unix shell")
+			}
+			actual, err := tC.password()
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+			if actual != tC.expected {
+				t.Errorf("Expected password: %q, Actual: %q", tC.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSASLPasswordIsReadOnce(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(file, []byte("first"), 0600); err != nil {
+		t.Fatalf("Failed to write the password file: %s", err)
+	}
+	password := PasswordFile(file)
+	if _, err := password(); err != nil {
+		t.Fatalf("Failed to read the password: %s", err)
+	}
+	if err := os.WriteFile(file, []byte("second"), 0600); err != nil {
+		t.Fatalf("Failed to update the password file: %s", err)
+	}
+	actual, err := password()
+	if err != nil {
+		t.Fatalf("Failed to read the password: %s", err)
+	}
+	if actual != "first" {
+		t.Errorf("Expected the password to be read once. Expected: first, Actual: %s", actual)
+	}
+}
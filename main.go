@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/xitonix/trubka/commands"
 	"github.com/xitonix/trubka/internal"
 	"github.com/xitonix/trubka/internal/output/format"
 )
@@ -28,7 +29,15 @@ func main() {
 }
 
 func exit(err error) {
+	code := 1
+	var exitErr *commands.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.Code
+		if exitErr.Err == nil {
+			os.Exit(code)
+		}
+	}
 	msg := fmt.Sprintf("ERROR: %s", internal.Title(err))
 	fmt.Fprintln(os.Stderr, format.Red(msg, enabledColor))
-	os.Exit(1)
+	os.Exit(code)
 }
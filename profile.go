@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/xitonix/trubka/commands"
+	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/config"
+)
+
+type profileValue struct {
+	flag  *kingpin.FlagClause
+	value string
+	// lenient ignores the value if it is not supported by the flag.
+	lenient bool
+}
+
+// applyProfile loads the selected cluster profile and sets the flags which have not been explicitly set by the user
+// to the values of the profile.
+//
+// The current profile is not applied if the brokers have been explicitly set, so that the TLS settings and the
+// credentials of the profile never get sent to a different cluster, unless the profile is explicitly selected.
+func applyProfile(ctx *kingpin.ParseContext, app *kingpin.Application, global *commands.GlobalParameters) error {
+	selected := ctx.SelectedCommand
+	if selected == nil || strings.Split(selected.FullCommand(), " ")[0] == "config" {
+		return nil
+	}
+
+	if internal.IsEmpty(global.ProfileName) && commands.IsFlagSet(ctx, app.GetFlag("brokers")) {
+		return nil
+	}
+
+	cfg, err := config.Load(global.ConfigFile)
+	if err != nil {
+		return err
+	}
+	profile, err := cfg.Profile(global.ProfileName)
+	if err != nil || profile == nil {
+		return err
+	}
+
+	values := []profileValue{
+		{flag: app.GetFlag("brokers"), value: profile.Brokers},
+		{flag: app.GetFlag("kafka-version"), value: profile.KafkaVersion},
+		{flag: selected.GetFlag("environment"), value: profile.Name},
+		{flag: selected.GetFlag("proto-root"), value: profile.ProtoRoot},
+	}
+
+	if tls := profile.TLS; tls != nil {
+		values = append(values,
+			profileValue{flag: app.GetFlag("tls"), value: "true"},
+			profileValue{flag: app.GetFlag("ca-cert"), value: tls.CACert},
+			profileValue{flag: app.GetFlag("tls-key-password"), value: tls.KeyPassword},
+			profileValue{flag: app.GetFlag("tls-server-name"), value: tls.ServerName},
+			profileValue{flag: app.GetFlag("tls-min-version"), value: tls.MinVersion},
+			profileValue{flag: app.GetFlag("tls-cipher-suites"), value: tls.CipherSuites})
+
+		// The client certificate of the profile is ignored if the user has explicitly provided a client certificate.
+		certificateFlags := []*kingpin.FlagClause{
+			app.GetFlag("client-cert"),
+			app.GetFlag("client-key"),
+			app.GetFlag("tls-pkcs12"),
+		}
+		if !anyFlagSet(ctx, certificateFlags) {
+			values = append(values,
+				profileValue{flag: certificateFlags[0], value: tls.ClientCert},
+				profileValue{flag: certificateFlags[1], value: tls.ClientKey},
+				profileValue{flag: certificateFlags[2], value: tls.PKCS12})
+		}
+		if tls.VerifySystemRoots {
+			values = append(values, profileValue{flag: app.GetFlag("tls-verify-system-roots"), value: "true"})
+		}
+	}
+
+	if sasl := profile.SASL; sasl != nil {
+		values = append(values,
+			profileValue{flag: app.GetFlag("sasl-mechanism"), value: sasl.Mechanism},
+			profileValue{flag: app.GetFlag("sasl-username"), value: sasl.Username},
+			profileValue{flag: app.GetFlag("sasl-version"), value: sasl.Version},
+			profileValue{flag: app.GetFlag("sasl-oauth-scopes"), value: sasl.OAuthScopes},
+			profileValue{flag: app.GetFlag("sasl-kerberos-config"), value: sasl.KerberosConfig},
+			profileValue{flag: app.GetFlag("sasl-kerberos-keytab"), value: sasl.KerberosKeyTab},
+			profileValue{flag: app.GetFlag("sasl-kerberos-service-name"), value: sasl.KerberosServiceName},
+			profileValue{flag: app.GetFlag("sasl-kerberos-realm"), value: sasl.KerberosRealm})
+
+		// The token sources of the profile are ignored if the user has explicitly provided the token.
+		tokenFlags := []*kingpin.FlagClause{
+			app.GetFlag("sasl-oauth-token"),
+			app.GetFlag("sasl-oauth-token-file"),
+			app.GetFlag("sasl-oauth-token-url"),
+		}
+		if !anyFlagSet(ctx, tokenFlags) {
+			values = append(values,
+				profileValue{flag: tokenFlags[1], value: sasl.OAuthTokenFile},
+				profileValue{flag: tokenFlags[2], value: sasl.OAuthTokenURL})
+		}
+
+		// The password sources of the profile are ignored if the user has explicitly provided the password.
+		passwordFlags := []*kingpin.FlagClause{
+			app.GetFlag("sasl-password"),
+			app.GetFlag("sasl-password-file"),
+			app.GetFlag("sasl-password-cmd"),
+		}
+		if !anyFlagSet(ctx, passwordFlags) {
+			values = append(values,
+				profileValue{flag: passwordFlags[0], value: sasl.Password},
+				profileValue{flag: passwordFlags[1], value: sasl.PasswordFile},
+				profileValue{flag: passwordFlags[2], value: sasl.PasswordCommand})
+		}
+	}
+
+	if strings.HasPrefix(selected.FullCommand(), "consume ") {
+		// Not all the output formats are supported by every consume command (eg. plain by consume proto).
+		values = append(values, profileValue{flag: selected.GetFlag("format"), value: profile.Format, lenient: true})
+	}
+
+	for _, v := range values {
+		if v.flag == nil || v.value == "" || commands.IsFlagSet(ctx, v.flag) {
+			continue
+		}
+		model := v.flag.Model()
+		if err := model.Value.Set(v.value); err != nil && !v.lenient {
+			return fmt.Errorf("invalid %s value in the %q profile: %w", model.Name, profile.Name, err)
+		}
+	}
+	return nil
+}
+
+func anyFlagSet(ctx *kingpin.ParseContext, flags []*kingpin.FlagClause) bool {
+	for _, flag := range flags {
+		if commands.IsFlagSet(ctx, flag) {
+			return true
+		}
+	}
+	return false
+}
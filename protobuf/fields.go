@@ -0,0 +1,74 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	//nolint:staticcheck
+	"github.com/golang/protobuf/proto"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+)
+
+// ToMap converts the message into a map of the field names, as defined in the proto file, to the field values.
+//
+// Unlike the Json representation of the message, the 64-bit integers are kept as numbers. The enums are converted
+// to their names, the bytes to base64 strings and the nested messages to maps. The unset message fields and
+// the unset members of the oneof fields are excluded.
+func ToMap(msg *dynamic.Message) map[string]interface{} {
+	result := make(map[string]interface{})
+	if msg == nil {
+		return result
+	}
+	for _, fd := range msg.GetKnownFields() {
+		if !msg.HasField(fd) && (fd.GetOneOf() != nil || (fd.GetMessageType() != nil && !fd.IsRepeated())) {
+			continue
+		}
+		result[fd.GetName()] = toFieldValue(fd, msg.GetField(fd))
+	}
+	return result
+}
+
+func toFieldValue(fd *desc.FieldDescriptor, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		valueType := fd.GetMapValueType()
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = toScalarValue(valueType, item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = toScalarValue(fd, item)
+		}
+		return result
+	default:
+		return toScalarValue(fd, value)
+	}
+}
+
+func toScalarValue(fd *desc.FieldDescriptor, value interface{}) interface{} {
+	switch v := value.(type) {
+	case *dynamic.Message:
+		return ToMap(v)
+	case proto.Message:
+		msg, err := dynamic.AsDynamicMessage(v)
+		if err != nil {
+			return nil
+		}
+		return ToMap(msg)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case int32:
+		if enum := fd.GetEnumType(); enum != nil {
+			if ev := enum.FindValueByNumber(v); ev != nil {
+				return ev.GetName()
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/IBM/sarama"
 	//nolint:staticcheck
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/jhump/protoreflect/dynamic"
 
 	"github.com/xitonix/trubka/internal"
+	"github.com/xitonix/trubka/internal/filter"
 )
 
 // Marshaller protocol buffers output serializer.
@@ -27,7 +29,8 @@ func NewMarshaller(
 	outputFormat string,
 	inclusions *internal.MessageMetadata,
 	enableColor bool,
-	highlightStyle string) *Marshaller {
+	highlightStyle string,
+	selector *filter.Selector) *Marshaller {
 	outputFormat = strings.TrimSpace(strings.ToLower(outputFormat))
 	m := &Marshaller{
 		outputFormat: outputFormat,
@@ -37,7 +40,8 @@ func NewMarshaller(
 			outputFormat,
 			inclusions,
 			enableColor,
-			highlightStyle),
+			highlightStyle,
+			selector),
 	}
 
 	var indentation string
@@ -49,7 +53,7 @@ func NewMarshaller(
 }
 
 // Marshal serialises the proto message into bytes.
-func (m *Marshaller) Marshal(msg *dynamic.Message, key []byte, ts time.Time, topic string, partition int32, offset int64) ([]byte, error) {
+func (m *Marshaller) Marshal(msg *dynamic.Message, key []byte, ts time.Time, topic string, partition int32, offset int64, headers []*sarama.RecordHeader) ([]byte, error) {
 	var (
 		result []byte
 		err    error
@@ -65,14 +69,14 @@ func (m *Marshaller) Marshal(msg *dynamic.Message, key []byte, ts time.Time, top
 		if err != nil {
 			return nil, err
 		}
-		return m.jsonProcessor.Process(message, key, ts, topic, partition, offset)
+		return m.jsonProcessor.Process(message, key, ts, topic, partition, offset, headers)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	result = m.inclusions.Render(key, result, ts, topic, partition, offset, m.outputFormat == internal.Base64Encoding)
+	result = m.inclusions.Render(key, result, ts, topic, partition, offset, headers, m.outputFormat == internal.Base64Encoding)
 
 	return result, nil
 }
@@ -0,0 +1,162 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AvroSchema Avro schema type.
+	AvroSchema = "AVRO"
+	// ProtobufSchema Protocol buffers schema type.
+	ProtobufSchema = "PROTOBUF"
+	// JSONSchema Json schema type.
+	JSONSchema = "JSON"
+)
+
+const (
+	contentType   = "application/vnd.schemaregistry.v1+json"
+	latestVersion = "latest"
+)
+
+// Reference represents a reference to another schema registered under a different subject.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema represents a schema stored in the registry.
+type Schema struct {
+	ID         int         `json:"id"`
+	Subject    string      `json:"subject,omitempty"`
+	Version    int         `json:"version,omitempty"`
+	Type       string      `json:"schemaType,omitempty"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+}
+
+type registryError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+// Client represents a Schema Registry client.
+//
+// The schemas fetched by ID are immutable and will be cached for the lifetime of the client.
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+	mux      sync.Mutex
+	cache    map[int]*Schema
+}
+
+// NewClient creates a new Schema Registry client.
+func NewClient(baseURL, username, password string, timeout time.Duration) (*Client, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema registry URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid schema registry URL %q: the scheme must be either http or https", baseURL)
+	}
+	return &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: timeout},
+		cache:    make(map[int]*Schema),
+	}, nil
+}
+
+// SchemaByID fetches the schema with the specified ID from the registry.
+//
+// The result will be served from the local cache, if the schema has been fetched before.
+func (c *Client) SchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if schema, ok := c.cache[id]; ok {
+		return schema, nil
+	}
+
+	schema := &Schema{}
+	err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the schema with ID %d: %w", id, err)
+	}
+	schema.ID = id
+	normaliseType(schema)
+	c.cache[id] = schema
+	return schema, nil
+}
+
+// LatestSchema fetches the latest version of the schema registered under the specified subject.
+func (c *Client) LatestSchema(ctx context.Context, subject string) (*Schema, error) {
+	return c.subjectSchema(ctx, subject, latestVersion)
+}
+
+// SubjectSchema fetches the specified version of the schema registered under the given subject.
+func (c *Client) SubjectSchema(ctx context.Context, subject string, version int) (*Schema, error) {
+	return c.subjectSchema(ctx, subject, fmt.Sprint(version))
+}
+
+func (c *Client) subjectSchema(ctx context.Context, subject string, version string) (*Schema, error) {
+	schema := &Schema{}
+	err := c.get(ctx, fmt.Sprintf("/subjects/%s/versions/%s", url.PathEscape(subject), version), schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version %s of the %s subject: %w", version, subject, err)
+	}
+	normaliseType(schema)
+	return schema, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var rErr registryError
+		if json.Unmarshal(body, &rErr) == nil && rErr.Message != "" {
+			return fmt.Errorf("%s (%d)", rErr.Message, rErr.Code)
+		}
+		return fmt.Errorf("unexpected response from the schema registry: %s", res.Status)
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// normaliseType sets the schema type to Avro if it's not been explicitly set by the server.
+func normaliseType(schema *Schema) {
+	schema.Type = strings.ToUpper(strings.TrimSpace(schema.Type))
+	if schema.Type == "" {
+		schema.Type = AvroSchema
+	}
+}
@@ -0,0 +1,272 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	//nolint:staticcheck
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/linkedin/goavro/v2"
+)
+
+// Serde serialises and deserialises the messages in the Confluent wire format.
+//
+// The compiled Avro codecs and protocol buffers descriptors are cached per schema ID.
+type Serde struct {
+	client         *Client
+	mux            sync.Mutex
+	avroCodecs     map[int]*goavro.Codec
+	protoFiles     map[int]*desc.FileDescriptor
+	jsonMarshaller *jsonpb.Marshaler
+}
+
+// NewSerde creates a new schema registry serialiser/deserialiser.
+func NewSerde(client *Client) *Serde {
+	return &Serde{
+		client:     client,
+		avroCodecs: make(map[int]*goavro.Codec),
+		protoFiles: make(map[int]*desc.FileDescriptor),
+		jsonMarshaller: &jsonpb.Marshaler{
+			OrigName: true,
+		},
+	}
+}
+
+// Decode decodes the Avro, Protobuf or Json Schema payload into Json.
+//
+// The payload must have been serialised in the Confluent wire format.
+func (s *Serde) Decode(ctx context.Context, payload []byte) ([]byte, error) {
+	id, payload, err := parseHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := s.client.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch schema.Type {
+	case AvroSchema:
+		return s.decodeAvro(schema, payload)
+	case ProtobufSchema:
+		return s.decodeProto(ctx, schema, payload)
+	case JSONSchema:
+		if !json.Valid(payload) {
+			return nil, errors.New("invalid json payload")
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+}
+
+// Encode encodes the Json value using the provided schema, in the Confluent wire format.
+//
+// The message type is only used for protocol buffer schemas to choose the message type from the schema file.
+// If not specified, the first message type defined in the file will be used.
+// Json Schema payloads are not validated against the schema.
+func (s *Serde) Encode(ctx context.Context, schema *Schema, messageType string, value []byte) ([]byte, error) {
+	var (
+		payload []byte
+		err     error
+	)
+	switch schema.Type {
+	case AvroSchema:
+		payload, err = s.encodeAvro(schema, value)
+	case ProtobufSchema:
+		payload, err = s.encodeProto(ctx, schema, messageType, value)
+	case JSONSchema:
+		if !json.Valid(value) {
+			return nil, errors.New("invalid json value")
+		}
+		payload = value
+	default:
+		return nil, fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(writeHeader(schema.ID), payload...), nil
+}
+
+func (s *Serde) decodeAvro(schema *Schema, payload []byte) ([]byte, error) {
+	codec, err := s.getAvroCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := codec.NativeFromBinary(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the Avro payload: %w", err)
+	}
+	return codec.TextualFromNative(nil, native)
+}
+
+func (s *Serde) encodeAvro(schema *Schema, value []byte) ([]byte, error) {
+	codec, err := s.getAvroCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := codec.NativeFromTextual(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the input as Avro json: %w", err)
+	}
+	return codec.BinaryFromNative(nil, native)
+}
+
+func (s *Serde) decodeProto(ctx context.Context, schema *Schema, payload []byte) ([]byte, error) {
+	fd, err := s.getProtoFile(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	indexes, payload, err := readMessageIndexes(payload)
+	if err != nil {
+		return nil, err
+	}
+	md, err := findMessageByIndexes(fd, indexes)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamic.NewMessage(md)
+	err = msg.Unmarshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the protobuf payload: %w", err)
+	}
+	return msg.MarshalJSONPB(s.jsonMarshaller)
+}
+
+func (s *Serde) encodeProto(ctx context.Context, schema *Schema, messageType string, value []byte) ([]byte, error) {
+	fd, err := s.getProtoFile(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+	var md *desc.MessageDescriptor
+	if messageType == "" {
+		messages := fd.GetMessageTypes()
+		if len(messages) == 0 {
+			return nil, errors.New("no message type has been defined in the protobuf schema")
+		}
+		md = messages[0]
+	} else {
+		md = fd.FindMessage(messageType)
+		if md == nil {
+			return nil, fmt.Errorf("%s has not been found in the protobuf schema", messageType)
+		}
+	}
+	msg := dynamic.NewMessage(md)
+	err = msg.UnmarshalJSON(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the input as json: %w", err)
+	}
+	payload, err := msg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return append(writeMessageIndexes(messageIndexes(md)), payload...), nil
+}
+
+func (s *Serde) getAvroCodec(schema *Schema) (*goavro.Codec, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if codec, ok := s.avroCodecs[schema.ID]; ok {
+		return codec, nil
+	}
+	if len(schema.References) > 0 {
+		return nil, errors.New("avro schema references are not supported")
+	}
+	codec, err := goavro.NewCodec(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	s.avroCodecs[schema.ID] = codec
+	return codec, nil
+}
+
+func (s *Serde) getProtoFile(ctx context.Context, schema *Schema) (*desc.FileDescriptor, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if fd, ok := s.protoFiles[schema.ID]; ok {
+		return fd, nil
+	}
+	name := fmt.Sprintf("schema_%d.proto", schema.ID)
+	files := map[string]string{
+		name: schema.Schema,
+	}
+	err := s.resolveReferences(ctx, schema.References, files)
+	if err != nil {
+		return nil, err
+	}
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(files),
+	}
+	fds, err := parser.ParseFiles(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the protobuf schema: %w", err)
+	}
+	s.protoFiles[schema.ID] = fds[0]
+	return fds[0], nil
+}
+
+func (s *Serde) resolveReferences(ctx context.Context, references []Reference, files map[string]string) error {
+	for _, ref := range references {
+		if _, ok := files[ref.Name]; ok {
+			continue
+		}
+		schema, err := s.client.SubjectSchema(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the schema reference %s: %w", ref.Name, err)
+		}
+		files[ref.Name] = schema.Schema
+		err = s.resolveReferences(ctx, schema.References, files)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findMessageByIndexes finds the message type within the file, using the path of indexes.
+func findMessageByIndexes(fd *desc.FileDescriptor, indexes []int) (*desc.MessageDescriptor, error) {
+	messages := fd.GetMessageTypes()
+	var md *desc.MessageDescriptor
+	for _, index := range indexes {
+		if index < 0 || index >= len(messages) {
+			return nil, fmt.Errorf("invalid protobuf message index %d", index)
+		}
+		md = messages[index]
+		messages = md.GetNestedMessageTypes()
+	}
+	if md == nil {
+		return nil, errors.New("no protobuf message index has been provided")
+	}
+	return md, nil
+}
+
+// messageIndexes returns the path of indexes to the message type within its file.
+func messageIndexes(md *desc.MessageDescriptor) []int {
+	var indexes []int
+	for {
+		var siblings []*desc.MessageDescriptor
+		parent, isNested := md.GetParent().(*desc.MessageDescriptor)
+		if isNested {
+			siblings = parent.GetNestedMessageTypes()
+		} else {
+			siblings = md.GetFile().GetMessageTypes()
+		}
+		for i, sibling := range siblings {
+			if sibling == md {
+				indexes = append([]int{i}, indexes...)
+				break
+			}
+		}
+		if !isNested {
+			return indexes
+		}
+		md = parent
+	}
+}
@@ -0,0 +1,298 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	avroSchemaID   = 1
+	protoSchemaID  = 2
+	jsonSchemaID   = 3
+	commonSchemaID = 4
+)
+
+const avroSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "int"}
+  ]
+}`
+
+const commonProtoSchema = `syntax = "proto3";
+package common;
+message Address {
+  string city = 1;
+}`
+
+const protoSchema = `syntax = "proto3";
+package users;
+import "common/address.proto";
+message User {
+  string name = 1;
+  int32 age = 2;
+  common.Address address = 3;
+}
+message Envelope {
+  message Event {
+    string id = 1;
+  }
+  Event event = 1;
+}`
+
+type fakeRegistry struct {
+	mux      sync.Mutex
+	requests map[string]int
+	server   *httptest.Server
+}
+
+func newFakeRegistry() *fakeRegistry {
+	f := &fakeRegistry{
+		requests: make(map[string]int),
+	}
+	schemas := map[int]*Schema{
+		avroSchemaID:   {Schema: avroSchema},
+		protoSchemaID:  {Schema: protoSchema, Type: ProtobufSchema, References: []Reference{{Name: "common/address.proto", Subject: "common", Version: 1}}},
+		jsonSchemaID:   {Schema: `{"type": "object"}`, Type: JSONSchema},
+		commonSchemaID: {Schema: commonProtoSchema, Type: ProtobufSchema},
+	}
+	subjects := map[string]int{
+		"users-value/versions/latest": protoSchemaID,
+		"common/versions/1":           commonSchemaID,
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mux.Lock()
+		f.requests[r.URL.Path]++
+		f.mux.Unlock()
+		var (
+			schema *Schema
+			id     int
+		)
+		if _, err := fmt.Sscanf(r.URL.Path, "/schemas/ids/%d", &id); err == nil {
+			schema = schemas[id]
+		} else if id, ok := subjects[strings.TrimPrefix(r.URL.Path, "/subjects/")]; ok {
+			s := *schemas[id]
+			s.ID = id
+			schema = &s
+		}
+		if schema == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(schema)
+	}))
+	return f
+}
+
+func (f *fakeRegistry) requestCount(path string) int {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.requests[path]
+}
+
+func TestSerdeRoundTrip(t *testing.T) {
+	testCases := []struct {
+		title         string
+		schemaID      int
+		messageType   string
+		value         string
+		expected      string
+		expectedError string
+	}{
+		{
+			title:    "avro",
+			schemaID: avroSchemaID,
+			value:    `{"name": "Alice", "age": 30}`,
+			expected: `{"name":"Alice","age":30}`,
+		},
+		{
+			title:    "protobuf with references",
+			schemaID: protoSchemaID,
+			value:    `{"name": "Alice", "age": 30, "address": {"city": "Sydney"}}`,
+			expected: `{"name":"Alice","age":30,"address":{"city":"Sydney"}}`,
+		},
+		{
+			title:       "protobuf nested message type",
+			schemaID:    protoSchemaID,
+			messageType: "users.Envelope.Event",
+			value:       `{"id": "ID"}`,
+			expected:    `{"id":"ID"}`,
+		},
+		{
+			title:    "json schema",
+			schemaID: jsonSchemaID,
+			value:    `{"name":"Alice"}`,
+			expected: `{"name":"Alice"}`,
+		},
+		{
+			title:         "invalid avro value",
+			schemaID:      avroSchemaID,
+			value:         `{"name": "Alice"}`,
+			expectedError: "failed to parse the input as Avro json",
+		},
+		{
+			title:         "invalid json value",
+			schemaID:      jsonSchemaID,
+			value:         `{"name":`,
+			expectedError: "invalid json value",
+		},
+		{
+			title:         "unknown protobuf message type",
+			schemaID:      protoSchemaID,
+			messageType:   "users.Unknown",
+			value:         `{}`,
+			expectedError: "users.Unknown has not been found in the protobuf schema",
+		},
+		{
+			title:         "unknown schema",
+			schemaID:      100,
+			expectedError: "Schema not found (40403)",
+		},
+	}
+	registry := newFakeRegistry()
+	defer registry.server.Close()
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			client, err := NewClient(registry.server.URL, "", "", time.Second)
+			if err != nil {
+				t.Fatalf("Did not expect an error, but received %s", err)
+			}
+			serde := NewSerde(client)
+			ctx := context.Background()
+			schema, err := client.SchemaByID(ctx, tC.schemaID)
+			if err == nil {
+				var encoded []byte
+				encoded, err = serde.Encode(ctx, schema, tC.messageType, []byte(tC.value))
+				if err == nil {
+					var decoded []byte
+					decoded, err = serde.Decode(ctx, encoded)
+					if err == nil && string(decoded) != tC.expected {
+						t.Errorf("Expected decoded value: %s, Actual: %s", tC.expected, decoded)
+					}
+				}
+			}
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSerdeDecodeInvalidPayload(t *testing.T) {
+	testCases := []struct {
+		title         string
+		payload       []byte
+		expectedError string
+	}{
+		{
+			title:         "empty payload",
+			expectedError: "the message is too short (0 bytes)",
+		},
+		{
+			title:         "invalid magic byte",
+			payload:       []byte{1, 0, 0, 0, 1, 2},
+			expectedError: "unknown magic byte 1",
+		},
+		{
+			title:         "invalid avro payload",
+			payload:       []byte{0, 0, 0, 0, avroSchemaID, 0xFF},
+			expectedError: "failed to decode the Avro payload",
+		},
+		{
+			title:         "invalid protobuf message index",
+			payload:       append(writeHeader(protoSchemaID), writeMessageIndexes([]int{5})...),
+			expectedError: "invalid protobuf message index 5",
+		},
+	}
+	registry := newFakeRegistry()
+	defer registry.server.Close()
+	client, err := NewClient(registry.server.URL, "", "", time.Second)
+	if err != nil {
+		t.Fatalf("Did not expect an error, but received %s", err)
+	}
+	serde := NewSerde(client)
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			_, err := serde.Decode(context.Background(), tC.payload)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestClientCachesSchemasByID(t *testing.T) {
+	registry := newFakeRegistry()
+	defer registry.server.Close()
+	client, err := NewClient(registry.server.URL, "", "", time.Second)
+	if err != nil {
+		t.Fatalf("Did not expect an error, but received %s", err)
+	}
+	serde := NewSerde(client)
+	schema, err := client.SchemaByID(context.Background(), avroSchemaID)
+	if err != nil {
+		t.Fatalf("Did not expect an error, but received %s", err)
+	}
+	encoded, err := serde.Encode(context.Background(), schema, "", []byte(`{"name": "Alice", "age": 30}`))
+	if err != nil {
+		t.Fatalf("Did not expect an error, but received %s", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := serde.Decode(context.Background(), encoded); err != nil {
+			t.Fatalf("Did not expect an error, but received %s", err)
+		}
+	}
+	if count := registry.requestCount("/schemas/ids/1"); count != 1 {
+		t.Errorf("Expected the schema to be fetched once, but it was fetched %d times", count)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testCases := []struct {
+		title         string
+		url           string
+		expectedError string
+	}{
+		{
+			title: "http url",
+			url:   "http://localhost:8081",
+		},
+		{
+			title: "https url with trailing slash",
+			url:   "https://localhost:8081/",
+		},
+		{
+			title:         "url without scheme",
+			url:           "localhost:8081",
+			expectedError: "the scheme must be either http or https",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.title, func(t *testing.T) {
+			_, err := NewClient(tC.url, "", "", time.Second)
+			if !checkError(err, tC.expectedError) {
+				t.Errorf("Expected error: %q, Actual: %s", tC.expectedError, err)
+			}
+		})
+	}
+}
+
+func checkError(actual error, expected string) bool {
+	if actual == nil {
+		return expected == ""
+	}
+	if expected == "" {
+		return false
+	}
+	return strings.Contains(actual.Error(), expected)
+}
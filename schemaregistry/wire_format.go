@@ -0,0 +1,75 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	magicByte  byte = 0
+	headerSize      = 5
+)
+
+// parseHeader extracts the schema ID from the Confluent wire format header.
+//
+// The method returns the schema ID and the remaining payload.
+func parseHeader(payload []byte) (int, []byte, error) {
+	if len(payload) < headerSize {
+		return 0, nil, fmt.Errorf("the message is too short (%d bytes) to be in the schema registry wire format", len(payload))
+	}
+	if payload[0] != magicByte {
+		return 0, nil, fmt.Errorf("unknown magic byte %d. The message has not been serialised using the schema registry wire format", payload[0])
+	}
+	return int(binary.BigEndian.Uint32(payload[1:headerSize])), payload[headerSize:], nil
+}
+
+// writeHeader returns the Confluent wire format header for the specified schema ID.
+func writeHeader(schemaID int) []byte {
+	header := make([]byte, headerSize)
+	header[0] = magicByte
+	binary.BigEndian.PutUint32(header[1:], uint32(schemaID))
+	return header
+}
+
+// readMessageIndexes reads the protocol buffers message indexes, following the wire format header.
+//
+// The indexes represent the path to the message type within the proto file.
+func readMessageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 {
+		return nil, nil, errors.New("failed to read the number of protobuf message indexes")
+	}
+	payload = payload[n:]
+	if count == 0 {
+		// The first message type of the file.
+		return []int{0}, payload, nil
+	}
+	if count < 0 || count > int64(len(payload)) {
+		return nil, nil, fmt.Errorf("invalid number of protobuf message indexes: %d", count)
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, nil, errors.New("failed to read the protobuf message indexes")
+		}
+		indexes[i] = int(index)
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
+
+// writeMessageIndexes encodes the protocol buffers message indexes.
+func writeMessageIndexes(indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		// Optimisation for the first message type of the file.
+		return []byte{0}
+	}
+	buf := make([]byte, 0, (len(indexes)+1)*binary.MaxVarintLen64)
+	buf = binary.AppendVarint(buf, int64(len(indexes)))
+	for _, index := range indexes {
+		buf = binary.AppendVarint(buf, int64(index))
+	}
+	return buf
+}